@@ -3,6 +3,9 @@ package main
 import (
 	"fmt"
 	docsPkg "github.com/Ditta1337/RemitlyInternshipTask2025/docs"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/auth"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/ratelimit"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -16,14 +19,61 @@ type application struct {
 	config config
 	store  store.Storage
 	logger *zap.SugaredLogger
+	// verifies bearer tokens, nil when they are not accepted
+	jwt *auth.JWTVerifier
+	// throttles clients, nil when rate limiting is off
+	limiter ratelimit.Limiter
 }
 
 type config struct {
-	addr       string
-	apiURL     string
-	db         dbConfig
-	env        string
-	apiVersion string
+	addr         string
+	apiURL       string
+	storage      string
+	sqlitePath   string
+	seedPath     string
+	db           dbConfig
+	env          string
+	apiVersion   string
+	cacheControl string
+	cache        cacheConfig
+	auth         authConfig
+	rateLimit    rateLimitConfig
+}
+
+// limits of every client, zero requests leave the class unlimited
+type rateLimitConfig struct {
+	// all requests of an IP, counted before authentication, so failed attempts are limited too
+	ip    ratelimit.Limit
+	read  ratelimit.Limit
+	write ratelimit.Limit
+}
+
+type authConfig struct {
+	// disabled auth lets every request through, for tests and local development
+	enabled bool
+	jwt     jwtConfig
+}
+
+type jwtConfig struct {
+	hmacSecret    string
+	publicKeyFile string
+	jwksFile      string
+	issuer        string
+	audience      string
+	scopeClaim    string
+	readScope     string
+	writeScope    string
+	leeway        string
+}
+
+// JWTs are accepted once any of their signing keys is set
+func (cfg jwtConfig) configured() bool {
+	return cfg.hmacSecret != "" || cfg.publicKeyFile != "" || cfg.jwksFile != ""
+}
+
+type cacheConfig struct {
+	size int
+	ttl  string
 }
 
 type dbConfig struct {
@@ -41,7 +91,6 @@ func (app *application) mount() chi.Router {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.StripSlashes)
-	r.Use(middleware.Timeout(60 * time.Second))
 
 	baseRoute := "/" + app.config.apiVersion
 	r.Route(baseRoute, func(r chi.Router) {
@@ -49,18 +98,66 @@ func (app *application) mount() chi.Router {
 		docsURL := fmt.Sprintf("%s/swagger/doc.json", app.config.addr)
 		r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL(docsURL)))
 
-		r.Route("/swift-codes", func(r chi.Router) {
-			r.Post("/", app.createBankHandler)
-
-			r.Route("/{swift-code}", func(r chi.Router) {
-				r.Get("/", app.getBankBySWIFTCodeHandler)
-				r.Delete("/", app.deleteBankHandler)
+		// everything but the docs needs an API key with the scope of the route,
+		// clients are then rate limited separately for reads and writes
+		r.Group(func(r chi.Router) {
+			// no principal is known yet, so requests are limited by IP
+			r.Use(app.rateLimit("ip", app.config.rateLimit.ip))
+			r.Use(app.authenticate)
+
+			read := chi.Chain(app.requireScope(model.ScopeRead), app.rateLimit("read", app.config.rateLimit.read)).Handler
+			write := chi.Chain(app.requireScope(model.ScopeWrite), app.rateLimit("write", app.config.rateLimit.write)).Handler
+			admin := chi.Chain(app.requireScope(model.ScopeAdmin), app.rateLimit("write", app.config.rateLimit.write)).Handler
+
+			// exports stream for as long as the client reads them and releases take long to apply,
+			// so they run without the request timeout
+			r.With(read).Get("/swift-codes/export", app.exportBanksHandler)
+			r.With(admin).Post("/admin/sync", app.syncBanksHandler)
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.Timeout(60 * time.Second))
+
+				r.Route("/swift-codes", func(r chi.Router) {
+					r.With(write).Post("/", app.createBankHandler)
+					r.With(write).Post("/import", app.importBanksHandler)
+					r.With(read).Post("/lookup", app.lookupBanksHandler)
+					r.With(read).Get("/search", app.searchBanksHandler)
+					r.With(read).Get("/integrity", app.checkIntegrityHandler)
+					r.With(admin).Post("/integrity/repair", app.repairIntegrityHandler)
+
+					r.Route("/{swift-code}", func(r chi.Router) {
+						r.With(read).Get("/", app.getBankBySWIFTCodeHandler)
+						r.With(write).Put("/", app.updateBankHandler)
+						r.With(write).Patch("/", app.patchBankHandler)
+						r.With(write).Delete("/", app.deleteBankHandler)
+						r.With(read).Get("/history", app.getBankHistoryHandler)
+						r.With(read).Get("/parse", app.parseSWIFTCodeHandler)
+					})
+					r.With(read).Get("/country/{countryISO2code}", app.getAllBanksByCountryISO2Handler)
+				})
+
+				r.Route("/countries", func(r chi.Router) {
+					r.Use(read)
+					r.Get("/", app.getAllCountriesHandler)
+					r.Get("/{iso2}", app.getCountryByISO2Handler)
+				})
+
+				r.With(read).Get("/institutions/{code}", app.getInstitutionHandler)
+
+				r.Route("/admin", func(r chi.Router) {
+					r.Use(admin)
+					r.Get("/cache", app.getCacheStatsHandler)
+				})
 			})
-			r.Get("/country/{countryISO2code}", app.getAllBanksByCountryISO2Handler)
 		})
 
 	})
 
+	// unknown routes and methods get problem details like every other error,
+	// set last to reach the routers mounted above
+	r.NotFound(app.routeNotFoundResponse)
+	r.MethodNotAllowed(app.methodNotAllowedResponse(r))
+
 	return r
 }
 
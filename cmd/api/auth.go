@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/auth"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"go.uber.org/zap"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+var (
+	errMissingCredentials = errors.New("missing credentials, send an API key in the " + apiKeyHeader + " header or a bearer token")
+	errInvalidAPIKey      = errors.New("invalid or expired API key")
+	errBearerDisabled     = errors.New("bearer tokens are not accepted, send an API key in the " + apiKeyHeader + " header")
+	errMissingScope       = errors.New("missing scope")
+)
+
+// principal is who made the request, authenticated by an API key or a bearer token
+type principal struct {
+	// api-key:<id> for API keys, the sub claim for tokens
+	subject string
+	scopes  []string
+}
+
+type principalCtxKey struct{}
+
+func principalFromContext(ctx context.Context) (principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(principal)
+	return p, ok
+}
+
+// authenticate rejects requests without a valid API key or bearer token and passes
+// who made the request to the next handlers in the request context. Changes made
+// with that context record the subject in bank history.
+func (app *application) authenticate(next http.Handler) http.Handler {
+	if !app.config.auth.enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p principal
+		var err error
+
+		if token, ok := bearerToken(r); ok {
+			p, err = app.authenticateBearerToken(token)
+		} else if key := r.Header.Get(apiKeyHeader); key != "" {
+			p, err = app.authenticateAPIKey(r.Context(), key)
+		} else {
+			err = errMissingCredentials
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, errMissingCredentials), errors.Is(err, errInvalidAPIKey),
+				errors.Is(err, errBearerDisabled), errors.Is(err, auth.ErrInvalidToken):
+				app.unauthorizedResponse(w, r, err)
+			default:
+				app.internalServerError(w, r, err)
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), principalCtxKey{}, p)
+		ctx = store.WithActor(ctx, p.subject)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (app *application) authenticateAPIKey(ctx context.Context, key string) (principal, error) {
+	apiKey, err := app.store.APIKeys.GetByHash(ctx, auth.HashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return principal{}, errInvalidAPIKey
+		}
+		return principal{}, err
+	}
+
+	if apiKey.Expired(time.Now()) {
+		return principal{}, errInvalidAPIKey
+	}
+
+	return principal{subject: "api-key:" + apiKey.ID, scopes: apiKey.Scopes}, nil
+}
+
+func (app *application) authenticateBearerToken(token string) (principal, error) {
+	if app.jwt == nil {
+		return principal{}, errBearerDisabled
+	}
+
+	verified, err := app.jwt.Verify(token)
+	if err != nil {
+		return principal{}, err
+	}
+
+	return principal{subject: verified.Subject, scopes: verified.Scopes}, nil
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+// requireScope rejects requests whose principal, set by authenticate, lacks the scope
+func (app *application) requireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !app.config.auth.enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := principalFromContext(r.Context())
+			if !ok || !slices.Contains(p.scopes, scope) {
+				app.forbiddenResponse(w, r, fmt.Errorf("%w, credentials need the %s scope", errMissingScope, scope))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestLogger names the subject of the request in log lines, when it is known
+func (app *application) requestLogger(r *http.Request) *zap.SugaredLogger {
+	if p, ok := principalFromContext(r.Context()); ok {
+		return app.logger.With("subject", p.subject)
+	}
+
+	return app.logger
+}
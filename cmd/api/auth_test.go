@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/auth"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	app := newMockApplication(t)
+	app.config.auth.enabled = true
+	mux := app.mount()
+
+	newKey := func(t *testing.T, expiresAt *time.Time, scopes ...string) string {
+		t.Helper()
+
+		key, apiKey, err := auth.NewAPIKey("test", scopes, expiresAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.store.APIKeys.Create(context.Background(), &apiKey); err != nil {
+			t.Fatal(err)
+		}
+
+		return key
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	readKey := newKey(t, nil, model.ScopeRead)
+	writeKey := newKey(t, nil, model.ScopeRead, model.ScopeWrite)
+	adminKey := newKey(t, nil, model.ScopeAdmin)
+	expiredKey := newKey(t, &expired, model.ScopeRead)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		key            string
+		expectedStatus int
+	}{
+		{"should reject missing key", http.MethodGet, "/swift-codes/ABCDPLPWXXX", "", "", http.StatusUnauthorized},
+		{"should reject unknown key", http.MethodGet, "/swift-codes/ABCDPLPWXXX", "", "swk_unknown_key", http.StatusUnauthorized},
+		{"should reject expired key", http.MethodGet, "/swift-codes/ABCDPLPWXXX", "", expiredKey, http.StatusUnauthorized},
+		{"should allow read with read scope", http.MethodGet, "/swift-codes/ABCDPLPWXXX", "", readKey, http.StatusOK},
+		{"should allow lookup with read scope", http.MethodPost, "/swift-codes/lookup", `{"swiftCodes": ["ABCDPLPWXXX"]}`, readKey, http.StatusOK},
+		{"should forbid write with read scope", http.MethodDelete, "/swift-codes/ABCDPLPW123", "", readKey, http.StatusForbidden},
+		{"should forbid read without read scope", http.MethodGet, "/countries/PL", "", adminKey, http.StatusForbidden},
+		{"should forbid admin with write scope", http.MethodGet, "/admin/cache", "", writeKey, http.StatusForbidden},
+		{"should allow admin with admin scope", http.MethodGet, "/admin/cache", "", adminKey, http.StatusOK},
+		{"should allow write with write scope", http.MethodDelete, "/swift-codes/ABCDPLPW123", "", writeKey, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, app.config.apiVersion+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+
+			rec := executeRequest(req, mux)
+			checkResponseCode(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusUnauthorized || tt.expectedStatus == http.StatusForbidden {
+				var res responses.Problem
+				if err := json.NewDecoder(rec.Body).Decode(&res); err != nil || res.Code == "" || res.Status != tt.expectedStatus {
+					t.Errorf("expected problem details, got %+v, %v", res, err)
+				}
+			}
+			if tt.expectedStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestBearerAuthentication(t *testing.T) {
+	hmacSecret := []byte("0123456789abcdef0123456789abcdef")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyFile := filepath.Join(t.TempDir(), "public.pem")
+	if err := os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := fmt.Sprintf(`{"keys": [{"kty": "EC", "kid": "ec-1", "use": "sig", "crv": "P-256", "x": %q, "y": %q}]}`,
+		base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+		base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+	)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksFile, []byte(jwks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	verifier, err := auth.NewJWTVerifier(auth.JWTOptions{
+		HMACSecret:    hmacSecret,
+		PublicKeyFile: publicKeyFile,
+		JWKSFile:      jwksFile,
+		Issuer:        "https://issuer.example.com",
+		Audience:      "swift-api",
+		ScopeClaim:    "scope",
+		ReadScope:     "swift:read",
+		WriteScope:    "swift:write",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := newMockApplication(t)
+	app.config.auth.enabled = true
+	app.jwt = verifier
+	mux := app.mount()
+
+	claims := func(subject, scope string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   subject,
+			"iss":   "https://issuer.example.com",
+			"aud":   "swift-api",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": scope,
+		}
+	}
+	with := func(claims jwt.MapClaims, name string, value any) jwt.MapClaims {
+		claims[name] = value
+		return claims
+	}
+	sign := func(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+		t.Helper()
+
+		token := jwt.NewWithClaims(method, claims)
+		if method == jwt.SigningMethodES256 {
+			token.Header["kid"] = "ec-1"
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return signed
+	}
+
+	otherSecret := []byte("fedcba9876543210fedcba9876543210")
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		expectedStatus int
+	}{
+		{"should allow HS256 token", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, hmacSecret, claims("hs-user", "swift:read")), http.StatusOK},
+		{"should allow RS256 token", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodRS256, rsaKey, claims("rs-user", "swift:read")), http.StatusOK},
+		{"should allow ES256 token from JWKS", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodES256, ecKey, claims("es-user", "swift:read")), http.StatusOK},
+		{"should allow scope claim array", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, hmacSecret, with(claims("hs-user", ""), "scope", []string{"swift:read"})), http.StatusOK},
+		{"should reject bad signature", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, otherSecret, claims("hs-user", "swift:read")), http.StatusUnauthorized},
+		{"should reject expired token", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, hmacSecret, with(claims("hs-user", "swift:read"), "exp", time.Now().Add(-time.Hour).Unix())), http.StatusUnauthorized},
+		{"should reject token without exp", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, hmacSecret, with(claims("hs-user", "swift:read"), "exp", nil)), http.StatusUnauthorized},
+		{"should reject token before nbf", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, hmacSecret, with(claims("hs-user", "swift:read"), "nbf", time.Now().Add(time.Hour).Unix())), http.StatusUnauthorized},
+		{"should reject wrong audience", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, hmacSecret, with(claims("hs-user", "swift:read"), "aud", "other-api")), http.StatusUnauthorized},
+		{"should reject wrong issuer", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, hmacSecret, with(claims("hs-user", "swift:read"), "iss", "https://other.example.com")), http.StatusUnauthorized},
+		{"should reject token without subject", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodHS256, hmacSecret, claims("", "swift:read")), http.StatusUnauthorized},
+		{"should reject unsigned token", http.MethodGet, "/swift-codes/ABCDPLPWXXX", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims("hs-user", "swift:read")), http.StatusUnauthorized},
+		{"should forbid write with read scope", http.MethodDelete, "/swift-codes/ABCDPLPW123", sign(t, jwt.SigningMethodHS256, hmacSecret, claims("hs-user", "swift:read")), http.StatusForbidden},
+		{"should forbid admin with write scope", http.MethodGet, "/admin/cache", sign(t, jwt.SigningMethodHS256, hmacSecret, claims("hs-user", "swift:read swift:write")), http.StatusForbidden},
+		{"should allow write with write scope", http.MethodDelete, "/swift-codes/ABCDPLPW123", sign(t, jwt.SigningMethodHS256, hmacSecret, claims("hs-user", "swift:read swift:write")), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, app.config.apiVersion+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rec := executeRequest(req, mux)
+			checkResponseCode(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate header")
+			}
+		})
+	}
+
+	t.Run("should record token subject in history", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPW123/history", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, hmacSecret, claims("hs-user", "swift:read")))
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var history responses.BankHistory
+		if err := json.NewDecoder(rec.Body).Decode(&history); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.BankHistory: %v", err)
+		}
+
+		last := history.Versions[len(history.Versions)-1]
+		if last.Operation != model.OperationDelete || last.ChangedBy == nil || *last.ChangedBy != "hs-user" {
+			t.Errorf("expected delete changed by hs-user, got %+v", last)
+		}
+	})
+
+	t.Run("should reject bearer tokens when not configured", func(t *testing.T) {
+		app := newMockApplication(t)
+		app.config.auth.enabled = true
+		mux := app.mount()
+
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+sign(t, jwt.SigningMethodHS256, hmacSecret, claims("hs-user", "swift:read")))
+
+		checkResponseCode(t, http.StatusUnauthorized, executeRequest(req, mux).Code)
+	})
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/bic"
+	dbPkg "github.com/Ditta1337/RemitlyInternshipTask2025/internal/db"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/requests"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
@@ -11,17 +13,34 @@ import (
 	"strings"
 )
 
+var (
+	errBankNotFound        = errors.New("bank not found")
+	errBankAlreadyExists   = errors.New("bank with this SWIFT code already exists")
+	errNoBanksInCountry    = errors.New("no banks found in the country")
+	errHeadquarterMismatch = errors.New("isHeadquarter does not match the swift code, only codes ending with XXX are headquarters")
+)
+
+// response headers with the SWIFT code path parameter as requested and as resolved,
+// e.g. "abcdplpw" resolves to "ABCDPLPWXXX"
+const (
+	requestedSwiftCodeHeader = "X-Requested-Swift-Code"
+	resolvedSwiftCodeHeader  = "X-Resolved-Swift-Code"
+)
+
 // CreateBank godoc
 //
 //	@Summary		Creates a bank
-//	@Description	Creates a bank
+//	@Description	Creates a bank. The country name is filled in from the country ISO2 code when omitted, a given name has to match the canonical one ignoring case and is stored in the canonical form.
 //	@Tags			banks
 //	@Accept			json
 //	@Produce		json
 //	@Param			payload	body		requests.BankPayload	true	"Bank payload"
 //	@Success		201		{object}	responses.Message
-//	@Failure		400		{object}	responses.Error
-//	@Failure		500		{object}	responses.Error
+//	@Failure		400		{object}	responses.Problem
+//	@Failure		409		{object}	responses.Problem
+//	@Failure		500		{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
 //	@Router			/swift-codes [post]
 func (app *application) createBankHandler(w http.ResponseWriter, r *http.Request) {
 	var payload requests.BankPayload
@@ -30,37 +49,25 @@ func (app *application) createBankHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := Validate.Struct(payload); err != nil {
+	bank, err := validateBankPayload(payload)
+	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
 	}
 
-	var address *string
-	if payload.Address == "" || len(strings.TrimSpace(payload.Address)) == 0 {
-		address = nil
-	} else {
-		address = &payload.Address
-	}
-
-	if *payload.IsHeadquarter && payload.SWIFTCode[8:] != "XXX" {
-		app.badRequestResponse(w, r, errors.New("isHeadquarters set to true, while swift code says otherwise"))
-	}
+	ctx := r.Context()
 
-	bank := &model.Bank{
-		SWIFTCode:     payload.SWIFTCode,
-		Address:       address,
-		BankName:      payload.BankName,
-		CountryISO2:   payload.CountryISO2,
-		CountryName:   payload.CountryName,
-		IsHeadquarter: *payload.IsHeadquarter,
+	if err := dbPkg.CanonicalizeCountryName(bank); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
 	}
 
-	ctx := r.Context()
-
 	if err := app.store.Banks.Create(ctx, bank); err != nil {
 		switch {
 		case errors.Is(err, store.ErrAlreadyExists):
-			app.badRequestResponse(w, r, err)
+			app.conflictResponse(w, r, errBankAlreadyExists)
+		case errors.Is(err, store.ErrConflict):
+			app.conflictResponse(w, r, err)
 		default:
 			app.internalServerError(w, r, err)
 		}
@@ -80,14 +87,28 @@ func (app *application) createBankHandler(w http.ResponseWriter, r *http.Request
 //	@Tags			banks
 //	@Accept			json
 //	@Produce		json
-//	@Param			swift-code	path		string		true	"SWIFT Code"
-//	@Success		200			{object}	interface{}	"Returns either a BankHeadquarter or BankBranch. See the API documentation for details."
-//	@Failure		400			{object}	responses.Error
-//	@Failure		404			{object}	responses.Error
-//	@Failure		500			{object}	responses.Error
+//	@Param			swift-code			path		string			true	"SWIFT Code"
+//	@Param			asOf				query		string			false	"Answer with the state at this time, RFC 3339 timestamp or YYYY-MM-DD date"
+//	@Param			If-None-Match		header		string			false	"ETag of a previously received response"
+//	@Param			If-Modified-Since	header		string			false	"Last-Modified of a previously received response"
+//	@Success		200					{object}	interface{}		"Returns either a BankHeadquarter or BankBranch. See the API documentation for details."
+//	@Header			200					{string}	ETag			"Changes whenever the bank or one of its branches changes"
+//	@Header			200					{string}	Last-Modified	"Latest change of the bank or one of its branches, not sent with asOf"
+//	@Success		304					"Not Modified"
+//	@Failure		400					{object}	responses.Problem
+//	@Failure		404					{object}	responses.Problem
+//	@Failure		500					{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
 //	@Router			/swift-codes/{swift-code} [get]
 func (app *application) getBankBySWIFTCodeHandler(w http.ResponseWriter, r *http.Request) {
-	swiftCode, err := parseSwiftCode(r)
+	swiftCode, err := parseSwiftCode(w, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	asOf, err := parseAsOf(r)
 	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
@@ -95,29 +116,37 @@ func (app *application) getBankBySWIFTCodeHandler(w http.ResponseWriter, r *http
 
 	ctx := r.Context()
 
-	banks, err := app.store.Banks.GetBySWIFTCode(ctx, swiftCode)
+	var banks []model.Bank
+	if asOf != nil {
+		banks, err = app.store.Banks.GetBySWIFTCodeAsOf(ctx, swiftCode, *asOf)
+	} else {
+		banks, err = app.store.Banks.GetBySWIFTCode(ctx, swiftCode)
+	}
 	if err != nil {
 		switch {
 		case errors.Is(err, store.ErrNotFound):
-			app.notFoundResponse(w, r, err)
+			app.notFoundResponse(w, r, errBankNotFound)
 		default:
 			app.internalServerError(w, r, err)
 		}
 		return
 	}
 
+	// changes of branches also move updatedAt of their headquarter forward
+	lastModified := latestUpdatedAt(banks)
+
 	firstBank := banks[0]
 	if firstBank.IsHeadquarter {
 		bankHeadquarter := mapBankToBankHeadquarter(firstBank, banks[1:])
 
-		if err := app.writeJSONResponse(w, http.StatusOK, bankHeadquarter); err != nil {
+		if err := app.writeCacheableJSONResponse(w, r, bankHeadquarter, banks, lastModified); err != nil {
 			app.internalServerError(w, r, err)
 			return
 		}
 	} else {
 		bankBranch := mapBankToBankBranch(firstBank)
 
-		if err := app.writeJSONResponse(w, http.StatusOK, bankBranch); err != nil {
+		if err := app.writeCacheableJSONResponse(w, r, bankBranch, banks[:1], lastModified); err != nil {
 			app.internalServerError(w, r, err)
 			return
 		}
@@ -127,44 +156,236 @@ func (app *application) getBankBySWIFTCodeHandler(w http.ResponseWriter, r *http
 // GetAllBanksByCountryISO2Code godoc
 //
 //	@Summary		Gets all banks with given Country ISO2 Code
-//	@Description	Gets all banks with given Country ISO2 Code
+//	@Description	Gets banks with given Country ISO2 Code, ordered by SWIFT code. All banks are returned, unless limit or cursor asks for a page.
 //	@Tags			banks
 //	@Accept			json
 //	@Produce		json
-//	@Param			countryISO2code	path		string	true	"Country ISO2 Code"
-//	@Success		200				{object}	responses.AllBanks
-//	@Failure		400				{object}	responses.Error
-//	@Failure		500				{object}	responses.Error
+//	@Param			countryISO2code		path		string	true	"Country ISO2 Code"
+//	@Param			limit				query		int		false	"Maximum number of banks in the page, 100 when only cursor is given"	minimum(1)	maximum(1000)
+//	@Param			cursor				query		string	false	"nextCursor returned with the previous page"
+//	@Param			asOf				query		string	false	"Answer with the state at this time, RFC 3339 timestamp or YYYY-MM-DD date"
+//	@Param			If-None-Match		header		string	false	"ETag of a previously received response"
+//	@Param			If-Modified-Since	header		string	false	"Last-Modified of a previously received response"
+//	@Success		200					{object}	responses.AllBanks
+//	@Header			200					{string}	ETag			"Changes whenever a bank in the page changes"
+//	@Header			200					{string}	Last-Modified	"Latest change of a bank in the country, removals included, not sent with asOf"
+//	@Success		304					"Not Modified"
+//	@Failure		400					{object}	responses.Problem
+//	@Failure		500					{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
 //	@Router			/swift-codes/country/{countryISO2code} [get]
 func (app *application) getAllBanksByCountryISO2Handler(w http.ResponseWriter, r *http.Request) {
 	countryISO := strings.ToUpper(chi.URLParam(r, "countryISO2code"))
 	if len(countryISO) != 2 {
-		app.badRequestResponse(w, r, errors.New("incorrect country ISO2 code length"))
+		app.badRequestResponse(w, r, errInvalidCountryISO2Length)
+		return
+	}
+
+	page, err := parsePage(r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
 		return
 	}
 
 	ctx := r.Context()
 
-	banks, err := app.store.Banks.GetAllByCountryISO2(ctx, countryISO)
+	// one bank more than requested tells if there is a next page
+	pageWithNext := page
+	if page.Limit > 0 {
+		pageWithNext.Limit = page.Limit + 1
+	}
+
+	var banks []model.Bank
+	if asOf != nil {
+		banks, err = app.store.Banks.GetAllByCountryISO2AsOf(ctx, countryISO, *asOf, pageWithNext)
+	} else {
+		banks, err = app.store.Banks.GetAllByCountryISO2(ctx, countryISO, pageWithNext)
+	}
 	if err != nil {
 		switch {
 		case errors.Is(err, store.ErrNotFound):
-
-			app.notFoundResponse(w, r, err)
+			app.notFoundResponse(w, r, errNoBanksInCountry)
 		default:
 			app.internalServerError(w, r, err)
 		}
 		return
 	}
 
-	firstBank := banks[0]
+	var nextCursor *string
+	if page.Limit > 0 && len(banks) > page.Limit {
+		banks = banks[:page.Limit]
+		cursor := encodeCursor(banks[len(banks)-1].SWIFTCode)
+		nextCursor = &cursor
+	}
+
+	var cursor *string
+	if page.After != "" {
+		cursor = optionalString(r.URL.Query().Get("cursor"))
+	}
+
 	allBanks := responses.AllBanks{
-		CountryISO2: firstBank.CountryISO2,
-		CountryName: firstBank.CountryName,
+		CountryISO2: countryISO,
 		SwiftCodes:  mapBanksToBankShorts(banks),
+		Limit:       page.Limit,
+		Cursor:      cursor,
+		NextCursor:  nextCursor,
+	}
+
+	if countryName, ok := store.CountryName(countryISO); ok {
+		allBanks.CountryName = countryName
+	} else if len(banks) > 0 {
+		// banks stored before countries were validated
+		allBanks.CountryName = banks[0].CountryName
+	}
+
+	// removing a bank moves no updatedAt of the remaining ones, but it moves the time of the country
+	lastModified := latestUpdatedAt(banks)
+	if asOf == nil {
+		countryUpdatedAt, err := app.store.Banks.GetCountryUpdatedAt(ctx, countryISO)
+		if err != nil {
+			app.internalServerError(w, r, err)
+			return
+		}
+		if countryUpdatedAt.After(lastModified) {
+			lastModified = countryUpdatedAt
+		}
+	}
+
+	if err := app.writeCacheableJSONResponse(w, r, allBanks, banks, lastModified); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// UpdateBank godoc
+//
+//	@Summary		Replaces a bank
+//	@Description	Replaces all fields of a bank. Changing the SWIFT code re-links branches and the headquarter of the bank.
+//	@Tags			banks
+//	@Accept			json
+//	@Produce		json
+//	@Param			swift-code	path		string					true	"SWIFT Code"
+//	@Param			payload		body		requests.BankPayload	true	"Bank payload"
+//	@Success		200			{object}	responses.Message
+//	@Failure		400			{object}	responses.Problem
+//	@Failure		404			{object}	responses.Problem
+//	@Failure		409			{object}	responses.Problem
+//	@Failure		500			{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/{swift-code} [put]
+func (app *application) updateBankHandler(w http.ResponseWriter, r *http.Request) {
+	swiftCode, err := parseSwiftCode(w, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	var payload requests.BankPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	bank, err := validateBankPayload(payload)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	app.updateBank(w, r, swiftCode, bank)
+}
+
+// PatchBank godoc
+//
+//	@Summary		Updates a bank
+//	@Description	Updates only the fields present in the payload, the result is validated like a full payload. Changing the SWIFT code re-links branches and the headquarter of the bank.
+//	@Tags			banks
+//	@Accept			json
+//	@Produce		json
+//	@Param			swift-code	path		string						true	"SWIFT Code"
+//	@Param			payload		body		requests.BankPatchPayload	true	"Bank patch payload"
+//	@Success		200			{object}	responses.Message
+//	@Failure		400			{object}	responses.Problem
+//	@Failure		404			{object}	responses.Problem
+//	@Failure		409			{object}	responses.Problem
+//	@Failure		500			{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/{swift-code} [patch]
+func (app *application) patchBankHandler(w http.ResponseWriter, r *http.Request) {
+	swiftCode, err := parseSwiftCode(w, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	var patch requests.BankPatchPayload
+	if err := readJSON(w, r, &patch); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	banks, err := app.store.Banks.GetBySWIFTCode(ctx, swiftCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundResponse(w, r, errBankNotFound)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	payload := mapBankToBankPayload(banks[0])
+	patch.Apply(&payload)
+
+	// the stored country name is filled in again for the new country
+	if patch.CountryISO2 != nil && patch.CountryName == nil {
+		payload.CountryName = ""
+	}
+
+	bank, err := validateBankPayload(payload)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	app.updateBank(w, r, swiftCode, bank)
+}
+
+func (app *application) updateBank(w http.ResponseWriter, r *http.Request, swiftCode string, bank *model.Bank) {
+	ctx := r.Context()
+
+	if err := dbPkg.CanonicalizeCountryName(bank); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
 	}
 
-	if err := app.writeJSONResponse(w, http.StatusOK, allBanks); err != nil {
+	if err := app.store.Banks.Update(ctx, swiftCode, bank); err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundResponse(w, r, errBankNotFound)
+		case errors.Is(err, store.ErrAlreadyExists):
+			app.conflictResponse(w, r, errBankAlreadyExists)
+		case errors.Is(err, store.ErrConflict):
+			app.conflictResponse(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, responses.Message{Message: "successfully updated bank in database"}); err != nil {
 		app.internalServerError(w, r, err)
 		return
 	}
@@ -179,11 +400,13 @@ func (app *application) getAllBanksByCountryISO2Handler(w http.ResponseWriter, r
 //	@Produce		json
 //	@Param			swift-code	path		string	true	"SWIFT Code"
 //	@Success		200			{object}	responses.Message
-//	@Failure		404			{object}	responses.Error
-//	@Failure		500			{object}	responses.Error
+//	@Failure		404			{object}	responses.Problem
+//	@Failure		500			{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
 //	@Router			/swift-codes/{swift-code} [delete]
 func (app *application) deleteBankHandler(w http.ResponseWriter, r *http.Request) {
-	swiftCode, err := parseSwiftCode(r)
+	swiftCode, err := parseSwiftCode(w, r)
 	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
@@ -194,7 +417,7 @@ func (app *application) deleteBankHandler(w http.ResponseWriter, r *http.Request
 	if err := app.store.Banks.Delete(ctx, swiftCode); err != nil {
 		switch {
 		case errors.Is(err, store.ErrNotFound):
-			app.notFoundResponse(w, r, err)
+			app.notFoundResponse(w, r, errBankNotFound)
 		default:
 			app.internalServerError(w, r, err)
 		}
@@ -207,15 +430,78 @@ func (app *application) deleteBankHandler(w http.ResponseWriter, r *http.Request
 	}
 }
 
-func parseSwiftCode(r *http.Request) (string, error) {
-	swiftCode := strings.ToUpper(chi.URLParam(r, "swift-code"))
+// parses the SWIFT code path parameter, the requested and resolved forms
+// of the code are reported in response headers
+func parseSwiftCode(w http.ResponseWriter, r *http.Request) (string, error) {
+	requestedSwiftCode := chi.URLParam(r, "swift-code")
+	w.Header().Set(requestedSwiftCodeHeader, requestedSwiftCode)
+
+	swiftCode, err := normalizeSwiftCode(requestedSwiftCode)
+	if err != nil {
+		return "", err
+	}
+	w.Header().Set(resolvedSwiftCodeHeader, swiftCode)
+
+	return swiftCode, nil
+}
+
+// normalizes the SWIFT code, BIC8 resolves to its XXX headquarter form, and checks its length.
+// Codes stored before they were validated against ISO 9362 stay reachable, new codes are
+// validated in full with their payload.
+func normalizeSwiftCode(code string) (string, error) {
+	swiftCode := bic.Normalize(code)
 	if len(swiftCode) != 11 {
-		return "", errors.New("incorrect SWIFT code length")
+		return "", bic.ErrInvalidLength
 	}
 
 	return swiftCode, nil
 }
 
+// validates the payload and maps it to a bank
+func validateBankPayload(payload requests.BankPayload) (*model.Bank, error) {
+	payload.SWIFTCode = bic.Normalize(payload.SWIFTCode)
+	payload.CountryISO2 = strings.ToUpper(strings.TrimSpace(payload.CountryISO2))
+
+	if err := Validate.Struct(payload); err != nil {
+		return nil, err
+	}
+
+	code, err := bic.ParseForCountry(payload.SWIFTCode, payload.CountryISO2)
+	if err != nil {
+		return nil, err
+	}
+
+	if *payload.IsHeadquarter != code.IsHeadquarter() {
+		return nil, errHeadquarterMismatch
+	}
+
+	return &model.Bank{
+		SWIFTCode:     payload.SWIFTCode,
+		Address:       optionalString(payload.Address),
+		BankName:      payload.BankName,
+		CountryISO2:   payload.CountryISO2,
+		CountryName:   payload.CountryName,
+		IsHeadquarter: *payload.IsHeadquarter,
+		TownName:      optionalString(payload.TownName),
+		TimeZone:      optionalString(payload.TimeZone),
+		CodeType:      optionalString(payload.CodeType),
+	}, nil
+}
+
+func mapBankToBankPayload(bank model.Bank) requests.BankPayload {
+	return requests.BankPayload{
+		SWIFTCode:     bank.SWIFTCode,
+		Address:       valueOrEmpty(bank.Address),
+		BankName:      bank.BankName,
+		CountryISO2:   bank.CountryISO2,
+		CountryName:   bank.CountryName,
+		IsHeadquarter: &bank.IsHeadquarter,
+		TownName:      valueOrEmpty(bank.TownName),
+		TimeZone:      valueOrEmpty(bank.TimeZone),
+		CodeType:      valueOrEmpty(bank.CodeType),
+	}
+}
+
 func mapBankToBankHeadquarter(bank model.Bank, branches []model.Bank) responses.BankHeadquarter {
 	return responses.BankHeadquarter{
 		SWIFTCode:     bank.SWIFTCode,
@@ -224,6 +510,9 @@ func mapBankToBankHeadquarter(bank model.Bank, branches []model.Bank) responses.
 		CountryISO2:   bank.CountryISO2,
 		CountryName:   bank.CountryName,
 		IsHeadquarter: bank.IsHeadquarter,
+		TownName:      bank.TownName,
+		TimeZone:      bank.TimeZone,
+		CodeType:      bank.CodeType,
 		Branches:      mapBanksToBankShorts(branches),
 	}
 }
@@ -236,11 +525,14 @@ func mapBankToBankBranch(bank model.Bank) responses.BankBranch {
 		CountryISO2:   bank.CountryISO2,
 		CountryName:   bank.CountryName,
 		IsHeadquarter: bank.IsHeadquarter,
+		TownName:      bank.TownName,
+		TimeZone:      bank.TimeZone,
+		CodeType:      bank.CodeType,
 	}
 }
 
 func mapBanksToBankShorts(banks []model.Bank) []responses.BankShort {
-	var shortBanks []responses.BankShort
+	shortBanks := make([]responses.BankShort, 0, len(banks))
 
 	for _, bank := range banks {
 		var shortBank responses.BankShort
@@ -250,9 +542,29 @@ func mapBanksToBankShorts(banks []model.Bank) []responses.BankShort {
 		shortBank.CountryName = bank.CountryName
 		shortBank.CountryISO2 = bank.CountryISO2
 		shortBank.IsHeadquarter = bank.IsHeadquarter
+		shortBank.TownName = bank.TownName
+		shortBank.TimeZone = bank.TimeZone
+		shortBank.CodeType = bank.CodeType
 
 		shortBanks = append(shortBanks, shortBank)
 	}
 
 	return shortBanks
 }
+
+// returns nil for blank strings, so optional columns are stored as NULL
+func optionalString(value string) *string {
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil
+	}
+
+	return &value
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
 	"io"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -16,11 +20,11 @@ func TestCreateBankHandler(t *testing.T) {
 
 	t.Run("should create headquarter bank", func(t *testing.T) {
 		payload := `{
-			"swiftCode": "FAKECODEXXX",
+			"swiftCode": "FAKEUSNYXXX",
 			"address": "Test Addr",
 			"bankName": "Headquarter bank US",
 			"countryISO2": "US",
-			"countryName": "United States",
+			"countryName": "United States of America",
 			"isHeadquarter": true
 		}`
 		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
@@ -52,11 +56,11 @@ func TestCreateBankHandler(t *testing.T) {
 
 	t.Run("should create branch bank", func(t *testing.T) {
 		payload := `{
-			"swiftCode": "FAKECODE123",
+			"swiftCode": "FAKEUSNY123",
 			"address": "Test Addr",
 			"bankName": "Branch bank US",
 			"countryISO2": "US",
-			"countryName": "United States",
+			"countryName": "United States of America",
 			"isHeadquarter": false
 		}`
 		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
@@ -103,11 +107,11 @@ func TestCreateBankHandler(t *testing.T) {
 	t.Run("swiftCode already exists in db", func(t *testing.T) {
 		// bank with this swiftCode already exists in db
 		payload := `{
-			"swiftCode": "ABCDEFGHXXX",
+			"swiftCode": "ABCDPLPWXXX",
 			"address": "this bank already exists",
-			"bankName": "Headquarter bank US",
-			"countryISO2": "US",
-			"countryName": "United States",
+			"bankName": "Headquarter bank PL",
+			"countryISO2": "PL",
+			"countryName": "Poland",
 			"isHeadquarter": true
 		}`
 
@@ -118,16 +122,16 @@ func TestCreateBankHandler(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		rec := executeRequest(req, mux)
-		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+		checkResponseCode(t, http.StatusConflict, rec.Code)
 	})
 
 	t.Run("validation error missing required field", func(t *testing.T) {
 		// missing required bankName field
 		payload := `{
-			"swiftCode": "ABCDEFGHXXX",
+			"swiftCode": "ABCDPLPWXXX",
 			"address": "Test Addr",
 			"countryISO2": "US",
-			"countryName": "United States",
+			"countryName": "United States of America",
 			"isHeadquarter": true
 		}`
 		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
@@ -147,7 +151,7 @@ func TestCreateBankHandler(t *testing.T) {
 			"address": "Test Addr",
 			"bankName": "Invalid Swift Bank",
 			"countryISO2": "US",
-			"countryName": "United States",
+			"countryName": "United States of America",
 			"isHeadquarter": false
 		}`
 		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
@@ -160,14 +164,56 @@ func TestCreateBankHandler(t *testing.T) {
 		checkResponseCode(t, http.StatusBadRequest, rec.Code)
 	})
 
+	t.Run("validation error invalid time zone", func(t *testing.T) {
+		// time zone must exist in the IANA tz database
+		payload := `{
+			"swiftCode": "FAKEUSNY456",
+			"address": "Test Addr",
+			"bankName": "Branch bank US",
+			"countryISO2": "US",
+			"countryName": "United States of America",
+			"isHeadquarter": false,
+			"townName": "NEW YORK",
+			"timeZone": "America/Atlantis"
+		}`
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("validation error swift code of other country", func(t *testing.T) {
+		// country code inside the swift code must match countryISO2
+		payload := `{
+			"swiftCode": "FAKEDEFFXXX",
+			"address": "Test Addr",
+			"bankName": "Headquarter bank US",
+			"countryISO2": "US",
+			"countryName": "United States of America",
+			"isHeadquarter": true
+		}`
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+
 	t.Run("isHeadquarter mismatch error", func(t *testing.T) {
 		// headquarter bank swift code must end with "XXX"
 		payload := `{
-			"swiftCode": "ABCDEFGH123",
+			"swiftCode": "ABCDPLPW123",
 			"address": "HQ Address",
 			"bankName": "Mismatch HQ Bank",
-			"countryISO2": "US",
-			"countryName": "United States",
+			"countryISO2": "PL",
+			"countryName": "Poland",
 			"isHeadquarter": true
 		}`
 		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
@@ -179,6 +225,26 @@ func TestCreateBankHandler(t *testing.T) {
 		rec := executeRequest(req, mux)
 		checkResponseCode(t, http.StatusBadRequest, rec.Code)
 	})
+
+	t.Run("isHeadquarter false mismatch error", func(t *testing.T) {
+		// swift code ending with "XXX" is always a headquarter
+		payload := `{
+			"swiftCode": "MISMPLPWXXX",
+			"address": "Branch Address",
+			"bankName": "Mismatch Branch Bank",
+			"countryISO2": "PL",
+			"countryName": "Poland",
+			"isHeadquarter": false
+		}`
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
 }
 
 func TestGetBankBySWIFTCode(t *testing.T) {
@@ -186,7 +252,7 @@ func TestGetBankBySWIFTCode(t *testing.T) {
 	mux := app.mount()
 
 	t.Run("should return correct headquarter bank", func(t *testing.T) {
-		swiftCode := "ABCDEFGHXXX"
+		swiftCode := "ABCDPLPWXXX"
 		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/"+swiftCode, nil)
 		if err != nil {
 			t.Fatal(err)
@@ -211,6 +277,12 @@ func TestGetBankBySWIFTCode(t *testing.T) {
 			t.Errorf("expected bank name: %s, got %s", expectedBankName, response.BankName)
 		}
 
+		expectedTimeZone := "Europe/Warsaw"
+
+		if response.TimeZone == nil || *response.TimeZone != expectedTimeZone {
+			t.Errorf("expected time zone: %s, got %v", expectedTimeZone, response.TimeZone)
+		}
+
 		if len(response.Branches) != expectedBankBranchesSize {
 			t.Errorf("expected bank to have %d branch bank, got %d", expectedBankBranchesSize, len(response.Branches))
 		}
@@ -219,7 +291,7 @@ func TestGetBankBySWIFTCode(t *testing.T) {
 	})
 
 	t.Run("should return correct branch bank", func(t *testing.T) {
-		swiftCode := "ABCDEFGH123"
+		swiftCode := "ABCDPLPW123"
 		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/"+swiftCode, nil)
 		if err != nil {
 			t.Fatal(err)
@@ -259,7 +331,7 @@ func TestGetBankBySWIFTCode(t *testing.T) {
 	})
 
 	t.Run("invalid swiftCode format", func(t *testing.T) {
-		invalidSwiftCode := "TOOSHORT"
+		invalidSwiftCode := "TOOSHORT1"
 		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/"+invalidSwiftCode, nil)
 		if err != nil {
 			t.Fatal(err)
@@ -295,7 +367,7 @@ func TestGetAllBanksByCountryISO2(t *testing.T) {
 		}
 
 		expectedCountryISO := "PL"
-		expectedCountryName := "Poland"
+		expectedCountryName := "POLAND"
 		expectedSwiftCodesSize := 2
 
 		if response.CountryISO2 != expectedCountryISO {
@@ -313,6 +385,99 @@ func TestGetAllBanksByCountryISO2(t *testing.T) {
 		checkResponseCode(t, http.StatusOK, rec.Code)
 	})
 
+	t.Run("should page through banks with cursor", func(t *testing.T) {
+		url := app.config.apiVersion + "/swift-codes/country/PL?limit=1"
+		var swiftCodes []string
+
+		for page := 0; page < 3; page++ {
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := executeRequest(req, mux)
+			checkResponseCode(t, http.StatusOK, rec.Code)
+
+			var response responses.AllBanks
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("cannot unmarshal response to expected response.AllBanks: %v", err)
+			}
+
+			for _, bank := range response.SwiftCodes {
+				swiftCodes = append(swiftCodes, bank.SWIFTCode)
+			}
+
+			if response.NextCursor == nil {
+				break
+			}
+			url = app.config.apiVersion + "/swift-codes/country/PL?limit=1&cursor=" + *response.NextCursor
+		}
+
+		expectedSwiftCodes := []string{"ABCDPLPW123", "ABCDPLPWXXX"}
+		if strings.Join(swiftCodes, ",") != strings.Join(expectedSwiftCodes, ",") {
+			t.Errorf("expected swift codes %v, got %v", expectedSwiftCodes, swiftCodes)
+		}
+	})
+
+	t.Run("should return all banks without limit and cursor", func(t *testing.T) {
+		app := newMockApplication(t)
+		mux := app.mount()
+
+		for i := range defaultPageLimit {
+			bank := model.Bank{
+				SWIFTCode:   fmt.Sprintf("MANYPLPW%03d", i),
+				BankName:    "Branch bank PL",
+				CountryISO2: "PL",
+				CountryName: "POLAND",
+			}
+			if err := app.store.Banks.Create(context.Background(), &bank); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/country/PL", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var response responses.AllBanks
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.AllBanks: %v", err)
+		}
+
+		if expected := defaultPageLimit + 2; len(response.SwiftCodes) != expected {
+			t.Errorf("expected %d swift codes, got %d", expected, len(response.SwiftCodes))
+		}
+		if response.NextCursor != nil {
+			t.Errorf("expected no next cursor, got %s", *response.NextCursor)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/country/PL?limit=0", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/country/PL?cursor=!!!", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+
 	t.Run("invalid country ISO2", func(t *testing.T) {
 		countryISO := "TOOLONG"
 		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/country/"+countryISO, nil)
@@ -338,12 +503,134 @@ func TestGetAllBanksByCountryISO2(t *testing.T) {
 	})
 }
 
+func TestUpdateBankHandler(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("should replace headquarter bank", func(t *testing.T) {
+		payload := `{
+			"swiftCode": "ABCDPLPWXXX",
+			"address": "New Addr",
+			"bankName": "Renamed headquarter bank PL",
+			"countryISO2": "PL",
+			"countryName": "Poland",
+			"isHeadquarter": true
+		}`
+		req, err := http.NewRequest(http.MethodPut, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		headquarter := getBankHeadquarter(t, app.config.apiVersion, mux, "ABCDPLPWXXX")
+
+		expectedBankName := "Renamed headquarter bank PL"
+		if headquarter.BankName != expectedBankName {
+			t.Errorf("expected bank name: %s, got %s", expectedBankName, headquarter.BankName)
+		}
+
+		if len(headquarter.Branches) != 1 {
+			t.Errorf("expected headquarter to keep 1 branch, got %d", len(headquarter.Branches))
+		}
+	})
+
+	t.Run("should patch branch and keep headquarter link", func(t *testing.T) {
+		payload := `{
+			"swiftCode": "ABCDPLPW456",
+			"address": "Patched Addr"
+		}`
+		req, err := http.NewRequest(http.MethodPatch, app.config.apiVersion+"/swift-codes/ABCDPLPW123", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		headquarter := getBankHeadquarter(t, app.config.apiVersion, mux, "ABCDPLPWXXX")
+
+		if len(headquarter.Branches) != 1 || headquarter.Branches[0].SWIFTCode != "ABCDPLPW456" {
+			t.Fatalf("expected headquarter to have renamed branch ABCDPLPW456, got %v", headquarter.Branches)
+		}
+
+		expectedAddress := "Patched Addr"
+		if address := headquarter.Branches[0].Address; address == nil || *address != expectedAddress {
+			t.Errorf("expected address: %s, got %v", expectedAddress, address)
+		}
+	})
+
+	t.Run("patch validation error invalid time zone", func(t *testing.T) {
+		payload := `{"timeZone": "Europe/Atlantis"}`
+		req, err := http.NewRequest(http.MethodPatch, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("swiftCode already exists in db", func(t *testing.T) {
+		payload := `{"swiftCode": "ABCDPLPWXXX", "isHeadquarter": true}`
+		req, err := http.NewRequest(http.MethodPatch, app.config.apiVersion+"/swift-codes/ABCDPLPW456", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("nonexistent swiftCode", func(t *testing.T) {
+		payload := `{
+			"swiftCode": "NONEPLPWXXX",
+			"bankName": "Nonexistent bank",
+			"countryISO2": "PL",
+			"countryName": "Poland",
+			"isHeadquarter": true
+		}`
+		req, err := http.NewRequest(http.MethodPut, app.config.apiVersion+"/swift-codes/NONEPLPWXXX", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func getBankHeadquarter(t *testing.T, apiVersion string, mux http.Handler, swiftCode string) responses.BankHeadquarter {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, apiVersion+"/swift-codes/"+swiftCode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := executeRequest(req, mux)
+	checkResponseCode(t, http.StatusOK, rec.Code)
+
+	var headquarter responses.BankHeadquarter
+	if err := json.NewDecoder(rec.Body).Decode(&headquarter); err != nil {
+		t.Fatalf("cannot unmarshal response to expected response.BankHeadquarter: %v", err)
+	}
+
+	return headquarter
+}
+
 func TestDeleteBankBySWIFTCode(t *testing.T) {
 	app := newMockApplication(t)
 	mux := app.mount()
 
 	t.Run("should delete bank", func(t *testing.T) {
-		swiftCode := "ABCDEFGHXXX"
+		swiftCode := "ABCDPLPWXXX"
 		req, err := http.NewRequest(http.MethodDelete, app.config.apiVersion+"/swift-codes/"+swiftCode, nil)
 		if err != nil {
 			t.Fatal(err)
@@ -383,7 +670,7 @@ func TestDeleteBankBySWIFTCode(t *testing.T) {
 	})
 
 	t.Run("invalid swiftCode format", func(t *testing.T) {
-		invalidSwiftCode := "TOOSHORT"
+		invalidSwiftCode := "TOOSHORT1"
 		req, err := http.NewRequest(http.MethodDelete, app.config.apiVersion+"/swift-codes/"+invalidSwiftCode, nil)
 		if err != nil {
 			t.Fatal(err)
@@ -394,3 +681,138 @@ func TestDeleteBankBySWIFTCode(t *testing.T) {
 		checkResponseCode(t, http.StatusBadRequest, rec.Code)
 	})
 }
+
+func TestConcurrentBankRequests(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			payload := fmt.Sprintf(`{
+				"swiftCode": "ABCDPLPW%03d",
+				"bankName": "Concurrent branch PL",
+				"countryISO2": "PL",
+				"countryName": "Poland",
+				"isHeadquarter": false
+			}`, 200+i)
+			req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			checkResponseCode(t, http.StatusCreated, executeRequest(req, mux).Code)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			checkResponseCode(t, http.StatusOK, executeRequest(req, mux).Code)
+		}()
+	}
+	wg.Wait()
+
+	headquarter := getBankHeadquarter(t, app.config.apiVersion, mux, "ABCDPLPWXXX")
+	if len(headquarter.Branches) != 21 {
+		t.Errorf("expected 21 branches, got %d", len(headquarter.Branches))
+	}
+}
+
+func TestSwiftCodeNormalization(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("should resolve BIC8 path parameter to headquarter", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/abcdplpw", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		if requested := rec.Header().Get(requestedSwiftCodeHeader); requested != "abcdplpw" {
+			t.Errorf("expected requested SWIFT code abcdplpw, got %q", requested)
+		}
+		if resolved := rec.Header().Get(resolvedSwiftCodeHeader); resolved != "ABCDPLPWXXX" {
+			t.Errorf("expected resolved SWIFT code ABCDPLPWXXX, got %q", resolved)
+		}
+
+		var headquarter responses.BankHeadquarter
+		if err := json.NewDecoder(rec.Body).Decode(&headquarter); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.BankHeadquarter: %v", err)
+		}
+		if headquarter.SWIFTCode != "ABCDPLPWXXX" {
+			t.Errorf("expected headquarter ABCDPLPWXXX, got %s", headquarter.SWIFTCode)
+		}
+	})
+
+	t.Run("should store normalized SWIFT code", func(t *testing.T) {
+		createBank(t, app.config.apiVersion, mux, `{
+			"swiftCode": " fake usny ",
+			"bankName": "Headquarter bank US",
+			"countryISO2": "us",
+			"isHeadquarter": true
+		}`)
+
+		headquarter := getBankHeadquarter(t, app.config.apiVersion, mux, "FAKEUSNYXXX")
+		if headquarter.CountryISO2 != "US" {
+			t.Errorf("expected country ISO2 code US, got %s", headquarter.CountryISO2)
+		}
+	})
+}
+
+func TestLegacySwiftCodes(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	// location code ending with letter O is not valid ISO 9362, but such banks were stored before
+	legacyBank := model.Bank{
+		SWIFTCode:   "LEGAPLPO123",
+		BankName:    "Legacy branch PL",
+		CountryISO2: "PL",
+		CountryName: "POLAND",
+	}
+	if err := app.store.Banks.Create(context.Background(), &legacyBank); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should return legacy bank", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/LEGAPLPO123", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should still reject legacy code when parsed", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/LEGAPLPO123/parse", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should delete legacy bank", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodDelete, app.config.apiVersion+"/swift-codes/LEGAPLPO123", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// writes data as a cacheable JSON response. The strong ETag hashes the body
+// together with the SWIFT code and updatedAt of every bank behind it, so a
+// headquarter gets a new ETag whenever one of its branches changes.
+// Last-Modified is only sent when it is not zero, answers built from history have none.
+// If-None-Match, or If-Modified-Since when it is absent, is answered with 304 Not Modified.
+func (app *application) writeCacheableJSONResponse(w http.ResponseWriter, r *http.Request, data any, banks []model.Bank, lastModified time.Time) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	hash.Write(body)
+
+	for _, bank := range banks {
+		hash.Write([]byte(bank.SWIFTCode))
+		hash.Write([]byte(bank.UpdatedAt.UTC().Format(time.RFC3339Nano)))
+	}
+
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if app.config.cacheControl != "" {
+		header.Set("Cache-Control", app.config.cacheControl)
+	}
+	// shared caches must not answer a client with a response cached for another one
+	if app.config.auth.enabled {
+		header.Set("Vary", "Authorization, "+apiKeyHeader)
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	return app.writeJSONResponse(w, http.StatusOK, json.RawMessage(body))
+}
+
+// returns the latest updatedAt of the banks, it is zero for answers built from history
+func latestUpdatedAt(banks []model.Bank) time.Time {
+	var latest time.Time
+	for _, bank := range banks {
+		if bank.UpdatedAt.After(latest) {
+			latest = bank.UpdatedAt
+		}
+	}
+
+	return latest
+}
+
+// tells if the client already has the response, If-Modified-Since is ignored
+// when If-None-Match is sent
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			// If-None-Match uses the weak comparison
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// Last-Modified has a resolution of seconds
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
+
+// GetCacheStats godoc
+//
+//	@Summary		Gets storage cache counters
+//	@Description	Counts hits, misses, evictions and invalidations of the in-process cache of bank lookups since the start of the server. Counters are zero when the cache is disabled with STORE_CACHE_SIZE=0.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	responses.CacheStats
+//	@Security		ApiKeyAuth
+//	@Router			/admin/cache [get]
+func (app *application) getCacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	var cacheStats responses.CacheStats
+
+	if cache, ok := app.store.Banks.(*store.CachedBankStore); ok {
+		stats := cache.Stats()
+		cacheStats = responses.CacheStats{
+			Enabled:       true,
+			Hits:          stats.Hits,
+			Misses:        stats.Misses,
+			Evictions:     stats.Evictions,
+			Invalidations: stats.Invalidations,
+			Entries:       stats.Entries,
+			Size:          stats.Size,
+		}
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, cacheStats); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
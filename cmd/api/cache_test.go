@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/auth"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	app := newMockApplication(t)
+	app.config.cacheControl = "private, max-age=60"
+	mux := app.mount()
+
+	getBank := func(t *testing.T, path string, header http.Header) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		return executeRequest(req, mux).Result()
+	}
+
+	for _, path := range []string{"/swift-codes/ABCDPLPWXXX", "/swift-codes/ABCDPLPW123", "/swift-codes/country/PL"} {
+		t.Run("should send validators and cache headers for "+path, func(t *testing.T) {
+			res := getBank(t, path, nil)
+			checkResponseCode(t, http.StatusOK, res.StatusCode)
+
+			etag := res.Header.Get("ETag")
+			if !strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, "W/") {
+				t.Errorf("expected strong ETag, got %q", etag)
+			}
+			if cacheControl := res.Header.Get("Cache-Control"); cacheControl != app.config.cacheControl {
+				t.Errorf("expected Cache-Control %q, got %q", app.config.cacheControl, cacheControl)
+			}
+			if contentType := res.Header.Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("expected Content-Type application/json, got %q", contentType)
+			}
+
+			res = getBank(t, path, http.Header{"If-None-Match": {etag}})
+			checkResponseCode(t, http.StatusNotModified, res.StatusCode)
+			if res.Header.Get("ETag") != etag {
+				t.Errorf("expected 304 to repeat ETag %s, got %s", etag, res.Header.Get("ETag"))
+			}
+
+			res = getBank(t, path, http.Header{"If-None-Match": {`"stale"`}})
+			checkResponseCode(t, http.StatusOK, res.StatusCode)
+		})
+	}
+
+	t.Run("should send Last-Modified", func(t *testing.T) {
+		for _, path := range []string{"/swift-codes/ABCDPLPWXXX", "/swift-codes/ABCDPLPW123", "/swift-codes/country/PL"} {
+			res := getBank(t, path, nil)
+			if res.Header.Get("Last-Modified") == "" {
+				t.Errorf("expected Last-Modified header for %s", path)
+			}
+			if acceptRanges := res.Header.Get("Accept-Ranges"); acceptRanges != "" {
+				t.Errorf("expected no Accept-Ranges header for %s, got %s", path, acceptRanges)
+			}
+
+			res = getBank(t, path, http.Header{"If-Modified-Since": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}})
+			checkResponseCode(t, http.StatusNotModified, res.StatusCode)
+
+			res = getBank(t, path, http.Header{"If-Modified-Since": {time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)}})
+			checkResponseCode(t, http.StatusOK, res.StatusCode)
+		}
+
+		asOf := time.Now().UTC().Format(time.RFC3339Nano)
+		if lastModified := getBank(t, "/swift-codes/ABCDPLPWXXX?asOf="+asOf, nil).Header.Get("Last-Modified"); lastModified != "" {
+			t.Errorf("expected no Last-Modified header with asOf, got %s", lastModified)
+		}
+	})
+
+	t.Run("should ignore range headers", func(t *testing.T) {
+		res := getBank(t, "/swift-codes/ABCDPLPWXXX", http.Header{"Range": {"bytes=0-1"}, "If-Unmodified-Since": {time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)}})
+		checkResponseCode(t, http.StatusOK, res.StatusCode)
+
+		var headquarter responses.BankHeadquarter
+		if err := json.NewDecoder(res.Body).Decode(&headquarter); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.BankHeadquarter: %v", err)
+		}
+	})
+
+	t.Run("should change headquarter ETag when a branch changes", func(t *testing.T) {
+		before := getBank(t, "/swift-codes/ABCDPLPWXXX", nil).Header.Get("ETag")
+
+		req, err := http.NewRequest(http.MethodPatch, app.config.apiVersion+"/swift-codes/ABCDPLPW123", strings.NewReader(`{"address": "New branch address"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkResponseCode(t, http.StatusOK, executeRequest(req, mux).Code)
+
+		res := getBank(t, "/swift-codes/ABCDPLPWXXX", http.Header{"If-None-Match": {before}})
+		checkResponseCode(t, http.StatusOK, res.StatusCode)
+		if after := res.Header.Get("ETag"); after == before {
+			t.Errorf("expected ETag to change after branch update, still %s", after)
+		}
+	})
+
+	// Last-Modified has a resolution of seconds
+	waitForNextSecond := func() {
+		time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	}
+
+	t.Run("should not answer 304 for headquarter after a branch is deleted", func(t *testing.T) {
+		lastModified := getBank(t, "/swift-codes/ABCDPLPWXXX", nil).Header.Get("Last-Modified")
+		waitForNextSecond()
+
+		req, err := http.NewRequest(http.MethodDelete, app.config.apiVersion+"/swift-codes/ABCDPLPW123", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkResponseCode(t, http.StatusOK, executeRequest(req, mux).Code)
+
+		res := getBank(t, "/swift-codes/ABCDPLPWXXX", http.Header{"If-Modified-Since": {lastModified}})
+		checkResponseCode(t, http.StatusOK, res.StatusCode)
+
+		var headquarter responses.BankHeadquarter
+		if err := json.NewDecoder(res.Body).Decode(&headquarter); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.BankHeadquarter: %v", err)
+		}
+		if len(headquarter.Branches) != 0 {
+			t.Errorf("expected deleted branch to be gone, got %+v", headquarter.Branches)
+		}
+	})
+
+	t.Run("should not answer 304 for country page after a bank is deleted", func(t *testing.T) {
+		payload := `{
+			"swiftCode": "EFGHPLPWXXX",
+			"address": "Test Addr",
+			"bankName": "Another headquarter bank PL",
+			"countryISO2": "PL",
+			"isHeadquarter": true
+		}`
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkResponseCode(t, http.StatusCreated, executeRequest(req, mux).Code)
+
+		lastModified := getBank(t, "/swift-codes/country/PL", nil).Header.Get("Last-Modified")
+		waitForNextSecond()
+
+		req, err = http.NewRequest(http.MethodDelete, app.config.apiVersion+"/swift-codes/EFGHPLPWXXX", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkResponseCode(t, http.StatusOK, executeRequest(req, mux).Code)
+
+		res := getBank(t, "/swift-codes/country/PL", http.Header{"If-Modified-Since": {lastModified}})
+		checkResponseCode(t, http.StatusOK, res.StatusCode)
+	})
+}
+
+func TestCacheHeadersWithAuth(t *testing.T) {
+	app := newMockApplication(t)
+	app.config.auth.enabled = true
+	mux := app.mount()
+
+	key, apiKey, err := auth.NewAPIKey("test", []string{model.ScopeRead}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.store.APIKeys.Create(context.Background(), &apiKey); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(apiKeyHeader, key)
+
+	rec := executeRequest(req, mux)
+	checkResponseCode(t, http.StatusOK, rec.Code)
+
+	if vary := rec.Header().Get("Vary"); vary != "Authorization, X-API-Key" {
+		t.Errorf("expected Vary: Authorization, X-API-Key, got %q", vary)
+	}
+}
+
+func TestCacheStatsHandler(t *testing.T) {
+	getStats := func(t *testing.T, app *application) responses.CacheStats {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/admin/cache", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, app.mount())
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var stats responses.CacheStats
+		if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.CacheStats: %v", err)
+		}
+		return stats
+	}
+
+	t.Run("should report disabled cache", func(t *testing.T) {
+		if stats := getStats(t, newMockApplication(t)); stats.Enabled {
+			t.Errorf("expected disabled cache, got %+v", stats)
+		}
+	})
+
+	t.Run("should count hits and misses of bank lookups", func(t *testing.T) {
+		app := newMockApplication(t)
+		app.store = store.NewCachedStorage(app.store, store.CacheOptions{Size: 10, TTL: time.Minute})
+		mux := app.mount()
+
+		for range 3 {
+			req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkResponseCode(t, http.StatusOK, executeRequest(req, mux).Code)
+		}
+
+		expected := responses.CacheStats{Enabled: true, Hits: 2, Misses: 1, Entries: 1, Size: 10}
+		if stats := getStats(t, app); stats != expected {
+			t.Errorf("expected stats %+v, got %+v", expected, stats)
+		}
+	})
+}
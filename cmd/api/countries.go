@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strings"
+)
+
+var (
+	errInvalidCountryISO2Length = errors.New("incorrect country ISO2 code length")
+	errCountryNotFound          = errors.New("country not found")
+)
+
+// GetAllCountries godoc
+//
+//	@Summary		Gets all countries
+//	@Description	Gets all ISO 3166 countries ordered by ISO2 code, with canonical names and numbers of stored banks and headquarters
+//	@Tags			countries
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	responses.Countries
+//	@Failure		500	{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/countries [get]
+func (app *application) getAllCountriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	countries, err := app.store.Countries.GetAll(ctx)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	mappedCountries := make([]responses.Country, 0, len(countries))
+	for _, country := range countries {
+		mappedCountries = append(mappedCountries, mapCountry(country))
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, responses.Countries{Countries: mappedCountries}); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// GetCountryByISO2 godoc
+//
+//	@Summary		Gets a country by ISO2 code
+//	@Description	Gets the canonical name of the country and numbers of its stored banks and headquarters
+//	@Tags			countries
+//	@Accept			json
+//	@Produce		json
+//	@Param			iso2	path		string	true	"Country ISO2 Code"
+//	@Success		200		{object}	responses.Country
+//	@Failure		400		{object}	responses.Problem
+//	@Failure		404		{object}	responses.Problem
+//	@Failure		500		{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/countries/{iso2} [get]
+func (app *application) getCountryByISO2Handler(w http.ResponseWriter, r *http.Request) {
+	countryISO := strings.ToUpper(chi.URLParam(r, "iso2"))
+	if len(countryISO) != 2 {
+		app.badRequestResponse(w, r, errInvalidCountryISO2Length)
+		return
+	}
+
+	ctx := r.Context()
+
+	country, err := app.store.Countries.GetByISO2(ctx, countryISO)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundResponse(w, r, errCountryNotFound)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, mapCountry(country)); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func mapCountry(country model.Country) responses.Country {
+	return responses.Country{
+		CountryISO2:      country.ISO2,
+		CountryName:      country.Name,
+		BankCount:        country.BankCount,
+		HeadquarterCount: country.HeadquarterCount,
+	}
+}
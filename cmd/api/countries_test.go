@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCountriesHandlers(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("should list all countries", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/countries", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var response responses.Countries
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.Countries: %v", err)
+		}
+
+		if len(response.Countries) != 250 {
+			t.Errorf("expected 250 countries, got %d", len(response.Countries))
+		}
+	})
+
+	t.Run("should get country with bank counts", func(t *testing.T) {
+		country := getCountry(t, app.config.apiVersion, mux, "pl")
+
+		expected := responses.Country{CountryISO2: "PL", CountryName: "POLAND", BankCount: 2, HeadquarterCount: 1}
+		if country != expected {
+			t.Errorf("expected %+v, got %+v", expected, country)
+		}
+	})
+
+	t.Run("unknown country", func(t *testing.T) {
+		checkBankStatus(t, mux, app.config.apiVersion+"/countries/QQ", http.StatusNotFound)
+	})
+
+	t.Run("incorrect country ISO2 code length", func(t *testing.T) {
+		checkBankStatus(t, mux, app.config.apiVersion+"/countries/POL", http.StatusBadRequest)
+	})
+}
+
+func TestCreateBankCountryName(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("should fill in missing country name", func(t *testing.T) {
+		createBank(t, app.config.apiVersion, mux, `{
+			"swiftCode": "FILLDEFFXXX",
+			"bankName": "Headquarter bank DE",
+			"countryISO2": "DE",
+			"isHeadquarter": true
+		}`)
+
+		headquarter := getBankHeadquarter(t, app.config.apiVersion, mux, "FILLDEFFXXX")
+		if headquarter.CountryName != "GERMANY" {
+			t.Errorf("expected country name GERMANY, got %s", headquarter.CountryName)
+		}
+	})
+
+	t.Run("should store canonical country name", func(t *testing.T) {
+		createBank(t, app.config.apiVersion, mux, `{
+			"swiftCode": "CASEDEFFXXX",
+			"bankName": "Headquarter bank DE",
+			"countryISO2": "DE",
+			"countryName": "Germany",
+			"isHeadquarter": true
+		}`)
+
+		headquarter := getBankHeadquarter(t, app.config.apiVersion, mux, "CASEDEFFXXX")
+		if headquarter.CountryName != "GERMANY" {
+			t.Errorf("expected country name GERMANY, got %s", headquarter.CountryName)
+		}
+	})
+
+	t.Run("should reject country name not matching ISO2 code", func(t *testing.T) {
+		payload := `{
+			"swiftCode": "POLSPLPWXXX",
+			"bankName": "Headquarter bank PL",
+			"countryISO2": "PL",
+			"countryName": "Polska",
+			"isHeadquarter": true
+		}`
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/POLSPLPWXXX", http.StatusNotFound)
+	})
+
+	t.Run("should count created banks", func(t *testing.T) {
+		country := getCountry(t, app.config.apiVersion, mux, "DE")
+		if country.BankCount != 2 || country.HeadquarterCount != 2 {
+			t.Errorf("expected 2 banks and 2 headquarters, got %+v", country)
+		}
+	})
+}
+
+func getCountry(t *testing.T, apiVersion string, mux http.Handler, countryISO2 string) responses.Country {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, apiVersion+"/countries/"+countryISO2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := executeRequest(req, mux)
+	checkResponseCode(t, http.StatusOK, rec.Code)
+
+	var country responses.Country
+	if err := json.NewDecoder(rec.Body).Decode(&country); err != nil {
+		t.Fatalf("cannot unmarshal response to expected response.Country: %v", err)
+	}
+
+	return country
+}
@@ -2,19 +2,47 @@ package main
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 )
 
 func (app *application) internalServerError(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Errorf("internal server error: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
-	app.writeJSONError(w, http.StatusInternalServerError, "the server encountered a problem")
+	app.requestLogger(r).Errorf("internal server error: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+	app.writeProblem(w, r, problem{status: http.StatusInternalServerError, code: "internal_error", detail: "the server encountered a problem"})
 }
 
 func (app *application) badRequestResponse(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Warnf("bad request response: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
-	app.writeJSONError(w, http.StatusBadRequest, err.Error())
+	app.requestLogger(r).Warnf("bad request response: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+	app.writeProblem(w, r, problemFromError(http.StatusBadRequest, "bad_request", err))
 }
 
 func (app *application) notFoundResponse(w http.ResponseWriter, r *http.Request, err error) {
-	app.logger.Warnf("not found response: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
-	app.writeJSONError(w, http.StatusNotFound, "resource not found")
+	app.requestLogger(r).Warnf("not found response: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+	app.writeProblem(w, r, problemFromError(http.StatusNotFound, "not_found", err))
+}
+
+func (app *application) conflictResponse(w http.ResponseWriter, r *http.Request, err error) {
+	app.requestLogger(r).Warnf("conflict response: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+	app.writeProblem(w, r, problemFromError(http.StatusConflict, "conflict", err))
+}
+
+func (app *application) unauthorizedResponse(w http.ResponseWriter, r *http.Request, err error) {
+	app.requestLogger(r).Warnf("unauthorized response: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+	w.Header().Set("WWW-Authenticate", `Bearer, APIKey header="`+apiKeyHeader+`"`)
+	app.writeProblem(w, r, problemFromError(http.StatusUnauthorized, "unauthorized", err))
+}
+
+func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	app.requestLogger(r).Warnf("rate limit exceeded response: %s, path: %s, retry after: %s", r.Method, r.URL.Path, retryAfter)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+	app.writeProblem(w, r, problem{
+		status: http.StatusTooManyRequests,
+		code:   "rate_limit_exceeded",
+		detail: "rate limit exceeded, retry in " + strconv.Itoa(seconds(retryAfter)) + "s",
+	})
+}
+
+func (app *application) forbiddenResponse(w http.ResponseWriter, r *http.Request, err error) {
+	app.requestLogger(r).Warnf("forbidden response: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+	app.writeProblem(w, r, problemFromError(http.StatusForbidden, "forbidden", err))
 }
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	dbPkg "github.com/Ditta1337/RemitlyInternshipTask2025/internal/db"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var errUnsupportedExportFormat = errors.New("unsupported export format")
+
+// number of exported banks after which the response is flushed to the client
+const exportFlushInterval = 500
+
+// time given to every flush of the export to reach the client, a client that stops
+// reading for longer fails the export, which releases the database connection of the stream
+var exportIdleTimeout = 30 * time.Second
+
+type bankEncoder interface {
+	begin() error
+	encode(model.Bank) error
+	// flushes buffered output of the encoder to the underlying writer
+	flush() error
+	end() error
+}
+
+// ExportBanks godoc
+//
+//	@Summary		Exports banks
+//	@Description	Streams the whole directory, or its part matching the filters. TSV has the same columns as the seed file, so it can be imported back.
+//	@Tags			banks
+//	@Produce		json,plain
+//	@Param			format			query		string	false	"Export format"	Enums(csv, tsv, json, ndjson)	default(json)
+//	@Param			country			query		string	false	"Country ISO2 Code"
+//	@Param			isHeadquarter	query		bool	false	"Export only headquarters or only branches"
+//	@Success		200				{array}		responses.ExportedBank
+//	@Failure		400				{object}	responses.Problem
+//	@Failure		500				{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/export [get]
+func (app *application) exportBanksHandler(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "json"
+	}
+
+	encoder, contentType, err := newBankEncoder(format, w)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	filter, err := parseBankFilter(r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// the export can take longer than the server write timeout, instead it has to make progress,
+	// the write deadline fails a stalled write and the timer stops the query behind it
+	controller := http.NewResponseController(w)
+	progress := time.AfterFunc(exportIdleTimeout, cancel)
+	defer progress.Stop()
+
+	extendDeadline := func() {
+		progress.Reset(exportIdleTimeout)
+		_ = controller.SetWriteDeadline(time.Now().Add(exportIdleTimeout))
+	}
+	extendDeadline()
+
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="swift-codes.%s"`, format))
+		w.WriteHeader(http.StatusOK)
+
+		return encoder.begin()
+	}
+
+	exported := 0
+	err = app.store.Banks.Stream(ctx, filter, func(bank model.Bank) error {
+		if err := start(); err != nil {
+			return err
+		}
+
+		if err := encoder.encode(bank); err != nil {
+			return err
+		}
+
+		exported++
+		if exported%exportFlushInterval == 0 {
+			if err := encoder.flush(); err != nil {
+				return err
+			}
+			if err := controller.Flush(); err != nil {
+				return err
+			}
+			extendDeadline()
+		}
+
+		return nil
+	})
+	if err == nil {
+		if err = start(); err == nil {
+			err = encoder.end()
+		}
+	}
+
+	if err != nil {
+		if !started {
+			app.internalServerError(w, r, err)
+			return
+		}
+
+		// the status code is already sent, aborting the connection before the end of the chunked
+		// body makes the client fail to read the export instead of taking it as complete
+		app.requestLogger(r).Errorf("export interrupted: %s, path: %s, exported: %d, error: %s", r.Method, r.URL.Path, exported, err.Error())
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func newBankEncoder(format string, w io.Writer) (bankEncoder, string, error) {
+	switch format {
+	case "csv":
+		return newRecordEncoder(w, ','), "text/csv; charset=utf-8", nil
+	case "tsv":
+		return newRecordEncoder(w, '\t'), "text/tab-separated-values; charset=utf-8", nil
+	case "json":
+		return &jsonArrayEncoder{w: w, encoder: json.NewEncoder(w)}, "application/json", nil
+	case "ndjson":
+		return &ndjsonEncoder{encoder: json.NewEncoder(w)}, "application/x-ndjson", nil
+	default:
+		return nil, "", fmt.Errorf("%w %q", errUnsupportedExportFormat, format)
+	}
+}
+
+func parseBankFilter(r *http.Request) (store.BankFilter, error) {
+	var filter store.BankFilter
+
+	if country := r.URL.Query().Get("country"); country != "" {
+		filter.CountryISO2 = strings.ToUpper(country)
+		if len(filter.CountryISO2) != 2 {
+			return filter, errInvalidCountryISO2Length
+		}
+	}
+
+	if r.URL.Query().Has("isHeadquarter") {
+		isHeadquarter, err := parseBoolParam(r, "isHeadquarter")
+		if err != nil {
+			return filter, err
+		}
+		filter.IsHeadquarter = &isHeadquarter
+	}
+
+	return filter, nil
+}
+
+// writes banks in the SWIFT directory file layout
+type recordEncoder struct {
+	writer *csv.Writer
+}
+
+func newRecordEncoder(w io.Writer, comma rune) *recordEncoder {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	return &recordEncoder{writer: writer}
+}
+
+func (e *recordEncoder) begin() error {
+	return e.writer.Write(dbPkg.RecordHeader())
+}
+
+func (e *recordEncoder) encode(bank model.Bank) error {
+	return e.writer.Write(dbPkg.FormatRecord(bank))
+}
+
+func (e *recordEncoder) flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *recordEncoder) end() error {
+	return e.flush()
+}
+
+// writes banks as a single JSON array, element by element
+type jsonArrayEncoder struct {
+	w       io.Writer
+	encoder *json.Encoder
+	count   int
+}
+
+func (e *jsonArrayEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonArrayEncoder) encode(bank model.Bank) error {
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+
+	return e.encoder.Encode(mapBankToExportedBank(bank))
+}
+
+func (e *jsonArrayEncoder) flush() error {
+	return nil
+}
+
+func (e *jsonArrayEncoder) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+// writes banks as newline delimited JSON, one bank per line
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonEncoder) begin() error {
+	return nil
+}
+
+func (e *ndjsonEncoder) encode(bank model.Bank) error {
+	return e.encoder.Encode(mapBankToExportedBank(bank))
+}
+
+func (e *ndjsonEncoder) flush() error {
+	return nil
+}
+
+func (e *ndjsonEncoder) end() error {
+	return nil
+}
+
+func mapBankToExportedBank(bank model.Bank) responses.ExportedBank {
+	return responses.ExportedBank{
+		SWIFTCode:            bank.SWIFTCode,
+		Address:              bank.Address,
+		BankName:             bank.BankName,
+		CountryISO2:          bank.CountryISO2,
+		CountryName:          bank.CountryName,
+		IsHeadquarter:        bank.IsHeadquarter,
+		HeadquarterSWIFTCode: bank.HeadquarterSWIFTCode,
+		TownName:             bank.TownName,
+		TimeZone:             bank.TimeZone,
+		CodeType:             bank.CodeType,
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingStreamStore fails a stream after its first bank
+type failingStreamStore struct {
+	store.BankStorage
+}
+
+func (s failingStreamStore) Stream(ctx context.Context, filter store.BankFilter, fn func(model.Bank) error) error {
+	streamed := false
+	err := s.BankStorage.Stream(ctx, filter, func(bank model.Bank) error {
+		if streamed {
+			return errors.New("connection lost")
+		}
+		streamed = true
+		return fn(bank)
+	})
+	return err
+}
+
+// stalledStreamStore stops sending banks after the first one, until the stream is canceled
+type stalledStreamStore struct {
+	store.BankStorage
+	canceled chan struct{}
+}
+
+func (s stalledStreamStore) Stream(ctx context.Context, filter store.BankFilter, fn func(model.Bank) error) error {
+	banks, err := s.GetAllByCountryISO2(ctx, "PL", store.Page{})
+	if err != nil {
+		return err
+	}
+	if err := fn(banks[0]); err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+	close(s.canceled)
+	return ctx.Err()
+}
+
+func TestExportBanksHandler(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("should export json array", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/export?format=json", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var banks []responses.ExportedBank
+		if err := json.NewDecoder(rec.Body).Decode(&banks); err != nil {
+			t.Fatalf("cannot unmarshal response to expected []response.ExportedBank: %v", err)
+		}
+
+		expectedBanksSize := 2
+		if len(banks) != expectedBanksSize {
+			t.Fatalf("expected %d banks, got %d", expectedBanksSize, len(banks))
+		}
+
+		expectedFirstSWIFTCode := "ABCDPLPW123"
+		if banks[0].SWIFTCode != expectedFirstSWIFTCode {
+			t.Errorf("expected banks ordered by SWIFT code, first: %s, got %s", expectedFirstSWIFTCode, banks[0].SWIFTCode)
+		}
+	})
+
+	t.Run("should export filtered ndjson", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/export?format=ndjson&country=pl&isHeadquarter=true", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var banks []responses.ExportedBank
+		scanner := bufio.NewScanner(rec.Body)
+		for scanner.Scan() {
+			var bank responses.ExportedBank
+			if err := json.Unmarshal(scanner.Bytes(), &bank); err != nil {
+				t.Fatalf("cannot unmarshal line to expected response.ExportedBank: %v", err)
+			}
+			banks = append(banks, bank)
+		}
+
+		if len(banks) != 1 || !banks[0].IsHeadquarter {
+			t.Errorf("expected only the headquarter bank, got %v", banks)
+		}
+	})
+
+	t.Run("tsv export should be importable", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/export?format=tsv", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		export := rec.Body.String()
+		expectedHeader := "COUNTRY ISO2 CODE\tSWIFT CODE\tCODE TYPE\tNAME\tADDRESS\tTOWN NAME\tCOUNTRY NAME\tTIME ZONE\n"
+		if !strings.HasPrefix(export, expectedHeader) {
+			t.Errorf("expected seed file header, got %q", strings.SplitN(export, "\n", 2)[0])
+		}
+
+		req, err = http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/import?dryRun=true", strings.NewReader(export))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec = executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		report := decodeImportReport(t, rec.Body)
+		checkImportReport(t, report, 0, 2, 0)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/export?format=xml", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should abort interrupted export", func(t *testing.T) {
+		app := newMockApplication(t)
+		app.store.Banks = failingStreamStore{app.store.Banks}
+		server := httptest.NewServer(app.mount())
+		defer server.Close()
+
+		// a short export fails before its buffered start is sent, a long one while the body is read
+		res, err := http.Get(server.URL + app.config.apiVersion + "/swift-codes/export?format=ndjson")
+		if err == nil {
+			defer res.Body.Close()
+			_, err = io.ReadAll(res.Body)
+		}
+		if err == nil {
+			t.Error("expected reading an interrupted export to fail")
+		}
+	})
+
+	t.Run("should cancel export without progress", func(t *testing.T) {
+		defer func(timeout time.Duration) { exportIdleTimeout = timeout }(exportIdleTimeout)
+		exportIdleTimeout = 50 * time.Millisecond
+
+		app := newMockApplication(t)
+		stalled := stalledStreamStore{BankStorage: app.store.Banks, canceled: make(chan struct{})}
+		app.store.Banks = stalled
+		server := httptest.NewServer(app.mount())
+		defer server.Close()
+
+		res, err := http.Get(server.URL + app.config.apiVersion + "/swift-codes/export?format=ndjson")
+		if err == nil {
+			defer res.Body.Close()
+			_, err = io.ReadAll(res.Body)
+		}
+		if err == nil {
+			t.Error("expected reading a canceled export to fail")
+		}
+
+		select {
+		case <-stalled.canceled:
+		case <-time.After(time.Second):
+			t.Error("expected the stream to be canceled")
+		}
+	})
+}
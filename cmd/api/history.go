@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"net/http"
+	"time"
+)
+
+var errInvalidAsOf = errors.New("invalid asOf value")
+
+// GetBankHistory godoc
+//
+//	@Summary		Gets history of a bank
+//	@Description	Gets every version of a bank stored under the SWIFT code, oldest first. Deleted banks end with a DELETE version holding their last state.
+//	@Tags			banks
+//	@Accept			json
+//	@Produce		json
+//	@Param			swift-code	path		string	true	"SWIFT Code"
+//	@Success		200			{object}	responses.BankHistory
+//	@Failure		400			{object}	responses.Problem
+//	@Failure		404			{object}	responses.Problem
+//	@Failure		500			{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/{swift-code}/history [get]
+func (app *application) getBankHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	swiftCode, err := parseSwiftCode(w, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	versions, err := app.store.Banks.GetHistory(ctx, swiftCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundResponse(w, r, errBankNotFound)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	history := responses.BankHistory{
+		SWIFTCode: swiftCode,
+		Versions:  mapBankVersions(versions),
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, history); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// reads the asOf query param, either RFC 3339 timestamp or a date meaning its midnight UTC,
+// returns nil when the current state is requested
+func parseAsOf(r *http.Request) (*time.Time, error) {
+	value := r.URL.Query().Get("asOf")
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if asOf, err := time.Parse(layout, value); err == nil {
+			return &asOf, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w %q, expected RFC 3339 timestamp or YYYY-MM-DD date", errInvalidAsOf, value)
+}
+
+func mapBankVersions(versions []model.BankVersion) []responses.BankVersion {
+	bankVersions := make([]responses.BankVersion, 0, len(versions))
+
+	for _, version := range versions {
+		bankVersions = append(bankVersions, responses.BankVersion{
+			Operation: version.Operation,
+			ValidFrom: version.ValidFrom,
+			ValidTo:   version.ValidTo,
+			ChangedBy: version.ChangedBy,
+			Bank:      mapBankToExportedBank(version.Bank),
+		})
+	}
+
+	return bankVersions
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBankHistory(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	beforeChanges := time.Now()
+
+	req, err := http.NewRequest(http.MethodPatch, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", strings.NewReader(`{"bankName": "Renamed headquarter bank PL"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResponseCode(t, http.StatusOK, executeRequest(req, mux).Code)
+
+	req, err = http.NewRequest(http.MethodDelete, app.config.apiVersion+"/swift-codes/ABCDPLPW123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResponseCode(t, http.StatusOK, executeRequest(req, mux).Code)
+
+	asOf := url.QueryEscape(beforeChanges.Format(time.RFC3339Nano))
+
+	t.Run("should return every version of a bank", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPW123/history", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var history responses.BankHistory
+		if err := json.NewDecoder(rec.Body).Decode(&history); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.BankHistory: %v", err)
+		}
+
+		expectedOperations := []string{model.OperationInsert, model.OperationDelete}
+		if len(history.Versions) != len(expectedOperations) {
+			t.Fatalf("expected %d versions, got %d", len(expectedOperations), len(history.Versions))
+		}
+
+		for i, version := range history.Versions {
+			if version.Operation != expectedOperations[i] {
+				t.Errorf("expected version %d operation %s, got %s", i, expectedOperations[i], version.Operation)
+			}
+		}
+
+		if history.Versions[0].ValidTo == nil {
+			t.Errorf("expected first version to be closed")
+		}
+	})
+
+	t.Run("should return headquarter as of given time", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX?asOf="+asOf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var headquarter responses.BankHeadquarter
+		if err := json.NewDecoder(rec.Body).Decode(&headquarter); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.BankHeadquarter: %v", err)
+		}
+
+		expectedBankName := "Headquarter bank PL"
+		if headquarter.BankName != expectedBankName {
+			t.Errorf("expected bank name: %s, got %s", expectedBankName, headquarter.BankName)
+		}
+
+		if len(headquarter.Branches) != 1 {
+			t.Errorf("expected headquarter to have 1 branch, got %d", len(headquarter.Branches))
+		}
+	})
+
+	t.Run("should return deleted branch as of given time", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/country/PL?asOf="+asOf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var response responses.AllBanks
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.AllBanks: %v", err)
+		}
+
+		expectedSwiftCodesSize := 2
+		if len(response.SwiftCodes) != expectedSwiftCodesSize {
+			t.Errorf("expected %d swift codes, got %d", expectedSwiftCodesSize, len(response.SwiftCodes))
+		}
+	})
+
+	t.Run("deleted branch is not found now", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPW123?asOf="+url.QueryEscape(time.Now().Format(time.RFC3339Nano)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("invalid asOf", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX?asOf=yesterday", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("nonexistent swiftCode", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/INVALIDXXXX/history", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusNotFound, rec.Code)
+	})
+}
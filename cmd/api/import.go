@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	dbPkg "github.com/Ditta1337/RemitlyInternshipTask2025/internal/db"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	errInvalidUpload         = errors.New("invalid multipart upload")
+	errMissingFile           = errors.New("missing file")
+	errUnsupportedFileFormat = errors.New("unsupported file format")
+	errInvalidParameter      = errors.New("invalid parameter")
+)
+
+const maxUploadBytes = 32 << 20 // 32mb
+
+// ImportBanks godoc
+//
+//	@Summary		Imports banks from a SWIFT directory file
+//	@Description	Imports banks from a TSV or CSV file with the same columns as the seed file. The file can be sent as multipart form field "file" or as a raw request body.
+//	@Tags			banks
+//	@Accept			mpfd,plain
+//	@Produce		json
+//	@Param			file	formData	file	false	"SWIFT directory file"
+//	@Param			format	query		string	false	"File format, detected from the file name when omitted"	Enums(tsv, csv)
+//	@Param			dryRun	query		bool	false	"Only report what would be imported"
+//	@Success		200		{object}	responses.ImportReport
+//	@Failure		400		{object}	responses.Problem
+//	@Failure		500		{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/import [post]
+func (app *application) importBanksHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+
+	file, fileName, err := readUpload(r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	comma, err := parseFileFormat(r.URL.Query().Get("format"), fileName)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	dryRun, err := parseBoolParam(r, "dryRun")
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	report, err := dbPkg.Import(ctx, app.store, file, dbPkg.ImportOptions{Comma: comma, DryRun: dryRun})
+	if err != nil {
+		app.importInterruptedResponse(w, r, err, report)
+		return
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, mapImportReport(report)); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// an import stopped by an error may have created banks already, the problem carries
+// the report of rows handled before it stopped
+func (app *application) importInterruptedResponse(w http.ResponseWriter, r *http.Request, err error, report *dbPkg.ImportReport) {
+	var p problem
+	if report != nil && report.Failed != nil {
+		app.requestLogger(r).Errorf("import interrupted: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+		p = problem{
+			status: http.StatusInternalServerError,
+			code:   "import_interrupted",
+			detail: fmt.Sprintf("the server encountered a problem at line %d, rows missing from the report were not imported", report.Failed.Line),
+		}
+	} else {
+		// the upload could not be read to the end
+		var maxBytesErr *http.MaxBytesError
+		if !errors.As(err, &maxBytesErr) {
+			err = fmt.Errorf("%w: %w", errInvalidUpload, err)
+		}
+		app.requestLogger(r).Warnf("bad request response: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+		p = problemFromError(http.StatusBadRequest, "bad_request", err)
+	}
+
+	if report != nil {
+		importReport := mapImportReport(report)
+		p.report = &importReport
+	}
+
+	app.writeProblem(w, r, p)
+}
+
+// returns the uploaded file, either from the multipart "file" field or the raw request body.
+// The file is streamed, fields sent before it are skipped and the ones after it are not read.
+func readUpload(r *http.Request) (io.ReadCloser, string, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, "", nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", errInvalidUpload, err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, "", fmt.Errorf("%w: no file field in the form", errMissingFile)
+		}
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, "", err
+			}
+			return nil, "", fmt.Errorf("%w: %w", errInvalidUpload, err)
+		}
+
+		if part.FormName() == "file" {
+			return part, part.FileName(), nil
+		}
+	}
+}
+
+func parseFileFormat(format, fileName string) (rune, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	}
+
+	switch format {
+	case "", "tsv":
+		return '\t', nil
+	case "csv":
+		return ',', nil
+	default:
+		return 0, fmt.Errorf("%w %q", errUnsupportedFileFormat, format)
+	}
+}
+
+// reads a boolean query param. Form values are not read, parsing a form would
+// consume a raw request body before the file is read from it.
+func parseBoolParam(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s has to be a boolean, got %q", errInvalidParameter, name, value)
+	}
+
+	return parsed, nil
+}
+
+func mapImportReport(report *dbPkg.ImportReport) responses.ImportReport {
+	return responses.ImportReport{
+		DryRun:   report.DryRun,
+		Created:  mapImportRows(report.Created),
+		Skipped:  mapImportRows(report.Skipped),
+		Rejected: mapImportRows(report.Rejected),
+		Failed:   mapImportRow(report.Failed),
+	}
+}
+
+func mapImportRow(row *dbPkg.ImportRow) *responses.ImportRow {
+	if row == nil {
+		return nil
+	}
+
+	return &responses.ImportRow{
+		Line:      row.Line,
+		SWIFTCode: row.SWIFTCode,
+		Reason:    row.Reason,
+	}
+}
+
+func mapImportRows(rows []dbPkg.ImportRow) []responses.ImportRow {
+	importRows := make([]responses.ImportRow, 0, len(rows))
+
+	for _, row := range rows {
+		importRows = append(importRows, responses.ImportRow{
+			Line:      row.Line,
+			SWIFTCode: row.SWIFTCode,
+			Reason:    row.Reason,
+		})
+	}
+
+	return importRows
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const importFile = "COUNTRY ISO2 CODE\tSWIFT CODE\tCODE TYPE\tNAME\tADDRESS\tTOWN NAME\tCOUNTRY NAME\tTIME ZONE\n" +
+	"DE\tIMPTDEFFXXX\tBIC11\tImported HQ\tSome Street 1\tBERLIN\tGERMANY\tEurope/Berlin\n" +
+	"DE\tIMPTDEFF123\tBIC11\tImported Branch\t\tBERLIN\tGERMANY\tEurope/Berlin\n" +
+	"PL\tABCDPLPWXXX\tBIC11\tHeadquarter bank PL\t\tWARSZAWA\tPOLAND\tEurope/Warsaw\n" +
+	"DE\tIMPTDEFF456\tBIC11\tBad Time Zone\t\tBERLIN\tGERMANY\tEurope/Atlantis\n" +
+	"DE\tTOOSHORT\n"
+
+// failingCreateStore fails to create the bank with the given SWIFT code
+type failingCreateStore struct {
+	store.BankStorage
+	swiftCode string
+}
+
+func (s failingCreateStore) Create(ctx context.Context, bank *model.Bank) error {
+	if bank.SWIFTCode == s.swiftCode {
+		return errors.New("connection reset")
+	}
+	return s.BankStorage.Create(ctx, bank)
+}
+
+func TestImportBanksHandler(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("dry run should report without creating banks", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/import?dryRun=true", strings.NewReader(importFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "text/tab-separated-values")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		report := decodeImportReport(t, rec.Body)
+		checkImportReport(t, report, 2, 1, 2)
+
+		if !report.DryRun {
+			t.Errorf("expected dry run report")
+		}
+
+		req, err = http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/IMPTDEFFXXX", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec = executeRequest(req, mux)
+		checkResponseCode(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should import multipart file", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		if err := writer.WriteField("comment", "fields before the file are skipped"); err != nil {
+			t.Fatal(err)
+		}
+		part, err := writer.CreateFormFile("file", "SWIFT_CODES.tsv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write([]byte(importFile)); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/import", &body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		report := decodeImportReport(t, rec.Body)
+		checkImportReport(t, report, 2, 1, 2)
+
+		expectedRejectedLine := 5
+		if report.Rejected[0].Line != expectedRejectedLine {
+			t.Errorf("expected first rejected line %d, got %d", expectedRejectedLine, report.Rejected[0].Line)
+		}
+
+		req, err = http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/IMPTDEFFXXX", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec = executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var headquarter responses.BankHeadquarter
+		if err := json.NewDecoder(rec.Body).Decode(&headquarter); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.BankHeadquarter: %v", err)
+		}
+
+		if len(headquarter.Branches) != 1 {
+			t.Errorf("expected imported headquarter to have 1 branch, got %d", len(headquarter.Branches))
+		}
+	})
+
+	t.Run("should import csv file", func(t *testing.T) {
+		payload := "PL,WXYZPLPWXXX,BIC11,CSV bank,\"Street 1, Warsaw\",WARSZAWA,POLAND,Europe/Warsaw\n"
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/import?format=csv", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "text/csv")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		report := decodeImportReport(t, rec.Body)
+		checkImportReport(t, report, 1, 0, 0)
+	})
+
+	t.Run("should import raw body sent as a form", func(t *testing.T) {
+		// curl --data-binary sends application/x-www-form-urlencoded by default
+		payload := "PL,FORMPLPWXXX,BIC11,Form bank,,WARSZAWA,POLAND,Europe/Warsaw\n"
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/import?format=csv&dryRun=false", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		report := decodeImportReport(t, rec.Body)
+		checkImportReport(t, report, 1, 0, 0)
+	})
+
+	t.Run("should canonicalize country names", func(t *testing.T) {
+		payload := "PL,NAMEPLPWXXX,BIC11,Lower case bank,,WARSZAWA,poland,Europe/Warsaw\n" +
+			"PL,NAMEPLPW123,BIC11,Local name bank,,WARSZAWA,Polska,Europe/Warsaw\n"
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/import?format=csv", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "text/csv")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		report := decodeImportReport(t, rec.Body)
+		checkImportReport(t, report, 1, 0, 1)
+		if len(report.Rejected) == 1 && report.Rejected[0].SWIFTCode != "NAMEPLPW123" {
+			t.Errorf("expected NAMEPLPW123 to be rejected, got %+v", report.Rejected[0])
+		}
+
+		req, err = http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/NAMEPLPWXXX", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec = executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var headquarter responses.BankHeadquarter
+		if err := json.NewDecoder(rec.Body).Decode(&headquarter); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.BankHeadquarter: %v", err)
+		}
+
+		if headquarter.CountryName != "POLAND" {
+			t.Errorf("expected canonical country name POLAND, got %s", headquarter.CountryName)
+		}
+	})
+
+	t.Run("should return partial report when storage fails", func(t *testing.T) {
+		banks := app.store.Banks
+		app.store.Banks = failingCreateStore{BankStorage: banks, swiftCode: "FAILDEFF123"}
+		defer func() { app.store.Banks = banks }()
+
+		payload := "DE,FAILDEFFXXX,BIC11,Failing HQ,,BERLIN,GERMANY,Europe/Berlin\n" +
+			"DE,FAILDEFF123,BIC11,Failing Branch,,BERLIN,GERMANY,Europe/Berlin\n"
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/import?format=csv", strings.NewReader(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, app.mount())
+		checkResponseCode(t, http.StatusInternalServerError, rec.Code)
+
+		var problem responses.Problem
+		if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.Problem: %v", err)
+		}
+
+		if problem.Code != "import_interrupted" {
+			t.Errorf("expected problem code import_interrupted, got %s", problem.Code)
+		}
+		if strings.Contains(problem.Detail, "connection reset") {
+			t.Errorf("expected storage error to stay out of the response, got %s", problem.Detail)
+		}
+		if problem.Report == nil {
+			t.Fatal("expected problem to carry the import report")
+		}
+
+		checkImportReport(t, *problem.Report, 1, 0, 0)
+		if problem.Report.Failed == nil || problem.Report.Failed.Line != 2 || problem.Report.Failed.SWIFTCode != "FAILDEFF123" {
+			t.Errorf("expected import to fail at line 2 with FAILDEFF123, got %+v", problem.Report.Failed)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/import?format=xlsx", strings.NewReader(importFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func decodeImportReport(t *testing.T, body io.Reader) responses.ImportReport {
+	t.Helper()
+
+	var report responses.ImportReport
+	if err := json.NewDecoder(body).Decode(&report); err != nil {
+		t.Fatalf("cannot unmarshal response to expected response.ImportReport: %v", err)
+	}
+
+	return report
+}
+
+func checkImportReport(t *testing.T, report responses.ImportReport, created, skipped, rejected int) {
+	t.Helper()
+
+	if len(report.Created) != created {
+		t.Errorf("expected %d created rows, got %d", created, len(report.Created))
+	}
+
+	if len(report.Skipped) != skipped {
+		t.Errorf("expected %d skipped rows, got %d", skipped, len(report.Skipped))
+	}
+
+	if len(report.Rejected) != rejected {
+		t.Errorf("expected %d rejected rows, got %d", rejected, len(report.Rejected))
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/bic"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var errInstitutionNotFound = errors.New("institution not found")
+
+// GetInstitution godoc
+//
+//	@Summary		Gets banks of an institution in every country
+//	@Description	Gets every headquarter sharing the 4 character institution code of SWIFT codes, with their branches, and the countries the institution has banks in
+//	@Tags			institutions
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string	true	"Institution code, the first 4 characters of SWIFT codes"
+//	@Success		200		{object}	responses.Institution
+//	@Failure		400		{object}	responses.Problem
+//	@Failure		404		{object}	responses.Problem
+//	@Failure		500		{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/institutions/{code} [get]
+func (app *application) getInstitutionHandler(w http.ResponseWriter, r *http.Request) {
+	institutionCode := strings.ToUpper(strings.TrimSpace(chi.URLParam(r, "code")))
+	if err := bic.ValidateInstitutionCode(institutionCode); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	banks, err := app.store.Banks.GetAllByInstitutionCode(ctx, institutionCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			app.notFoundResponse(w, r, errInstitutionNotFound)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	institution := mapBanksToInstitution(institutionCode, banks)
+
+	// banks stored before countries were validated keep their country name
+	for i, country := range institution.Countries {
+		if countryName, ok := store.CountryName(country.CountryISO2); ok {
+			institution.Countries[i].CountryName = countryName
+		}
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, institution); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// groups banks ordered by SWIFT code under their headquarters,
+// countries are ordered by ISO2 code and count banks of the institution only
+func mapBanksToInstitution(institutionCode string, banks []model.Bank) responses.Institution {
+	headquarters := make(map[string]bool)
+	for _, bank := range banks {
+		if bank.IsHeadquarter {
+			headquarters[bank.SWIFTCode] = true
+		}
+	}
+
+	branches := make(map[string][]model.Bank)
+	var unlinkedBranches []model.Bank
+	for _, bank := range banks {
+		if bank.IsHeadquarter {
+			continue
+		}
+
+		if bank.HeadquarterSWIFTCode != nil && headquarters[*bank.HeadquarterSWIFTCode] {
+			branches[*bank.HeadquarterSWIFTCode] = append(branches[*bank.HeadquarterSWIFTCode], bank)
+		} else {
+			unlinkedBranches = append(unlinkedBranches, bank)
+		}
+	}
+
+	institution := responses.Institution{
+		InstitutionCode:  institutionCode,
+		Countries:        []responses.Country{},
+		Headquarters:     []responses.BankHeadquarter{},
+		UnlinkedBranches: mapBanksToBankShorts(unlinkedBranches),
+	}
+
+	countries := make(map[string]int)
+	for _, bank := range banks {
+		if bank.IsHeadquarter {
+			institution.Headquarters = append(institution.Headquarters, mapBankToBankHeadquarter(bank, branches[bank.SWIFTCode]))
+		}
+
+		index, ok := countries[bank.CountryISO2]
+		if !ok {
+			index = len(institution.Countries)
+			countries[bank.CountryISO2] = index
+			institution.Countries = append(institution.Countries, responses.Country{
+				CountryISO2: bank.CountryISO2,
+				CountryName: bank.CountryName,
+			})
+		}
+
+		institution.Countries[index].BankCount++
+		if bank.IsHeadquarter {
+			institution.Countries[index].HeadquarterCount++
+		}
+	}
+
+	slices.SortFunc(institution.Countries, func(a, b responses.Country) int {
+		return strings.Compare(a.CountryISO2, b.CountryISO2)
+	})
+
+	return institution
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"net/http"
+	"testing"
+)
+
+func TestGetInstitutionHandler(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	createBank(t, app.config.apiVersion, mux, `{
+		"swiftCode": "ABCDDEFFXXX",
+		"bankName": "Headquarter bank DE",
+		"countryISO2": "DE",
+		"isHeadquarter": true
+	}`)
+	createBank(t, app.config.apiVersion, mux, `{
+		"swiftCode": "ABCDUS33123",
+		"bankName": "Unlinked branch US",
+		"countryISO2": "US",
+		"isHeadquarter": false
+	}`)
+
+	t.Run("should group banks of every country", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/institutions/abcd", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var institution responses.Institution
+		if err := json.NewDecoder(rec.Body).Decode(&institution); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.Institution: %v", err)
+		}
+
+		if institution.InstitutionCode != "ABCD" {
+			t.Errorf("expected institution code ABCD, got %s", institution.InstitutionCode)
+		}
+
+		expectedCountries := []responses.Country{
+			{CountryISO2: "DE", CountryName: "GERMANY", BankCount: 1, HeadquarterCount: 1},
+			{CountryISO2: "PL", CountryName: "POLAND", BankCount: 2, HeadquarterCount: 1},
+			{CountryISO2: "US", CountryName: "UNITED STATES OF AMERICA", BankCount: 1, HeadquarterCount: 0},
+		}
+		if len(institution.Countries) != len(expectedCountries) {
+			t.Fatalf("expected countries %+v, got %+v", expectedCountries, institution.Countries)
+		}
+		for i, country := range institution.Countries {
+			if country != expectedCountries[i] {
+				t.Errorf("expected country %+v, got %+v", expectedCountries[i], country)
+			}
+		}
+
+		if len(institution.Headquarters) != 2 {
+			t.Fatalf("expected 2 headquarters, got %d", len(institution.Headquarters))
+		}
+		if institution.Headquarters[0].SWIFTCode != "ABCDDEFFXXX" || len(institution.Headquarters[0].Branches) != 0 {
+			t.Errorf("expected ABCDDEFFXXX without branches, got %+v", institution.Headquarters[0])
+		}
+		polishHeadquarter := institution.Headquarters[1]
+		if polishHeadquarter.SWIFTCode != "ABCDPLPWXXX" || len(polishHeadquarter.Branches) != 1 || polishHeadquarter.Branches[0].SWIFTCode != "ABCDPLPW123" {
+			t.Errorf("expected ABCDPLPWXXX with branch ABCDPLPW123, got %+v", polishHeadquarter)
+		}
+
+		if len(institution.UnlinkedBranches) != 1 || institution.UnlinkedBranches[0].SWIFTCode != "ABCDUS33123" {
+			t.Errorf("expected unlinked branch ABCDUS33123, got %+v", institution.UnlinkedBranches)
+		}
+	})
+
+	t.Run("unknown institution", func(t *testing.T) {
+		checkBankStatus(t, mux, app.config.apiVersion+"/institutions/QQQQ", http.StatusNotFound)
+	})
+
+	t.Run("invalid institution code", func(t *testing.T) {
+		checkBankStatus(t, mux, app.config.apiVersion+"/institutions/ABC", http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"net/http"
+)
+
+// CheckIntegrity godoc
+//
+//	@Summary		Checks bank hierarchy
+//	@Description	Reports orphan branches not linked to their existing headquarter, wrong headquarter links and isHeadquarter values disagreeing with the XXX branch code.
+//	@Tags			banks
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	responses.IntegrityReport
+//	@Failure		500	{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/integrity [get]
+func (app *application) checkIntegrityHandler(w http.ResponseWriter, r *http.Request) {
+	app.integrity(w, r, false)
+}
+
+// RepairIntegrity godoc
+//
+//	@Summary		Repairs bank hierarchy
+//	@Description	Fixes issues reported by the integrity check, isHeadquarter follows the XXX branch code and branches are linked to their existing headquarter. Returns the repaired issues.
+//	@Tags			banks
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	responses.IntegrityReport
+//	@Failure		500	{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Router			/swift-codes/integrity/repair [post]
+func (app *application) repairIntegrityHandler(w http.ResponseWriter, r *http.Request) {
+	app.integrity(w, r, true)
+}
+
+func (app *application) integrity(w http.ResponseWriter, r *http.Request, repair bool) {
+	ctx := r.Context()
+
+	issues, err := app.store.Banks.CheckIntegrity(ctx, repair)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	report := responses.IntegrityReport{
+		Repaired: repair,
+		Issues:   mapIntegrityIssues(issues),
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, report); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func mapIntegrityIssues(issues []model.IntegrityIssue) []responses.IntegrityIssue {
+	mappedIssues := make([]responses.IntegrityIssue, 0, len(issues))
+
+	for _, issue := range issues {
+		mappedIssues = append(mappedIssues, responses.IntegrityIssue{
+			SWIFTCode:                    issue.SWIFTCode,
+			Type:                         issue.Type,
+			IsHeadquarter:                issue.IsHeadquarter,
+			HeadquarterSWIFTCode:         issue.HeadquarterSWIFTCode,
+			ExpectedIsHeadquarter:        issue.ExpectedIsHeadquarter,
+			ExpectedHeadquarterSWIFTCode: issue.ExpectedHeadquarterSWIFTCode,
+		})
+	}
+
+	return mappedIssues
+}
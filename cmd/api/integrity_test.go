@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHeadquarterAdoptsBranches(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	// branch is created before its headquarter
+	createBank(t, app.config.apiVersion, mux, `{
+		"swiftCode": "ORPHPLPW123",
+		"bankName": "Orphan branch PL",
+		"countryISO2": "PL",
+		"countryName": "Poland",
+		"isHeadquarter": false
+	}`)
+	createBank(t, app.config.apiVersion, mux, `{
+		"swiftCode": "ORPHPLPWXXX",
+		"bankName": "Late headquarter PL",
+		"countryISO2": "PL",
+		"countryName": "Poland",
+		"isHeadquarter": true
+	}`)
+
+	headquarter := getBankHeadquarter(t, app.config.apiVersion, mux, "ORPHPLPWXXX")
+	if len(headquarter.Branches) != 1 || headquarter.Branches[0].SWIFTCode != "ORPHPLPW123" {
+		t.Fatalf("expected headquarter to adopt branch ORPHPLPW123, got %v", headquarter.Branches)
+	}
+}
+
+func TestIntegrityHandlers(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("should report no issues", func(t *testing.T) {
+		report := checkIntegrity(t, app.config.apiVersion, mux, http.MethodGet, "/swift-codes/integrity")
+		if len(report.Issues) != 0 {
+			t.Errorf("expected no issues, got %v", report.Issues)
+		}
+	})
+
+	// headquarter SWIFT code stored as a branch before payloads were checked against the code
+	legacyBank := model.Bank{
+		SWIFTCode:     "FLAGPLPWXXX",
+		BankName:      "Not a headquarter PL",
+		CountryISO2:   "PL",
+		CountryName:   "POLAND",
+		IsHeadquarter: false,
+	}
+	if err := app.store.Banks.Create(context.Background(), &legacyBank); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should report headquarter flag mismatch", func(t *testing.T) {
+		report := checkIntegrity(t, app.config.apiVersion, mux, http.MethodGet, "/swift-codes/integrity")
+		if report.Repaired {
+			t.Errorf("expected report without repair")
+		}
+		if len(report.Issues) != 1 {
+			t.Fatalf("expected 1 issue, got %v", report.Issues)
+		}
+
+		issue := report.Issues[0]
+		if issue.SWIFTCode != "FLAGPLPWXXX" || issue.Type != model.IssueHeadquarterFlagMismatch || !issue.ExpectedIsHeadquarter {
+			t.Errorf("expected headquarter flag mismatch of FLAGPLPWXXX, got %+v", issue)
+		}
+	})
+
+	t.Run("should repair issues", func(t *testing.T) {
+		report := checkIntegrity(t, app.config.apiVersion, mux, http.MethodPost, "/swift-codes/integrity/repair")
+		if !report.Repaired || len(report.Issues) != 1 {
+			t.Fatalf("expected 1 repaired issue, got %+v", report)
+		}
+
+		headquarter := getBankHeadquarter(t, app.config.apiVersion, mux, "FLAGPLPWXXX")
+		if !headquarter.IsHeadquarter {
+			t.Errorf("expected FLAGPLPWXXX to be a headquarter after repair")
+		}
+
+		report = checkIntegrity(t, app.config.apiVersion, mux, http.MethodGet, "/swift-codes/integrity")
+		if len(report.Issues) != 0 {
+			t.Errorf("expected no issues after repair, got %v", report.Issues)
+		}
+	})
+}
+
+func createBank(t *testing.T, apiVersion string, mux http.Handler, payload string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, apiVersion+"/swift-codes", strings.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	checkResponseCode(t, http.StatusCreated, executeRequest(req, mux).Code)
+}
+
+func checkIntegrity(t *testing.T, apiVersion string, mux http.Handler, method, path string) responses.IntegrityReport {
+	t.Helper()
+
+	req, err := http.NewRequest(method, apiVersion+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := executeRequest(req, mux)
+	checkResponseCode(t, http.StatusOK, rec.Code)
+
+	var report responses.IntegrityReport
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("cannot unmarshal response to expected response.IntegrityReport: %v", err)
+	}
+
+	return report
+}
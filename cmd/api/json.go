@@ -2,15 +2,25 @@ package main
 
 import (
 	"encoding/json"
-	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
 	"github.com/go-playground/validator/v10"
 	"net/http"
+	"reflect"
+	"strings"
 )
 
 var Validate *validator.Validate
 
 func init() {
 	Validate = validator.New(validator.WithRequiredStructEnabled())
+
+	// validation errors name fields as they are sent in JSON
+	Validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
 }
 
 func writeJSON(w http.ResponseWriter, status int, data any) error {
@@ -29,13 +39,6 @@ func readJSON(w http.ResponseWriter, r *http.Request, data any) error {
 	return decoder.Decode(data)
 }
 
-func (app *application) writeJSONError(w http.ResponseWriter, status int, message string) {
-
-	if err := writeJSON(w, status, &responses.Error{Error: message}); err != nil {
-		app.logger.Errorf("error writing JSOn error: %s", err.Error())
-	}
-}
-
 func (app *application) writeJSONResponse(w http.ResponseWriter, status int, data any) error {
 	return writeJSON(w, status, data)
 }
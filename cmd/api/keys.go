@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/auth"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"go.uber.org/zap"
+	"os"
+	"time"
+)
+
+const keysUsage = "usage: keys create --name <name> [--scopes read,write,admin] [--expires-in 720h] | keys list | keys revoke <id>"
+
+// runKeys is the "keys" subcommand, it manages API keys of the configured database
+// and prints them as JSON to stdout
+func runKeys(ctx context.Context, cfg config, logger *zap.SugaredLogger, args []string) {
+	if len(args) == 0 {
+		logger.Fatal(keysUsage)
+	}
+
+	db, store := openDB(cfg, logger)
+	defer db.Close()
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("keys create", flag.ExitOnError)
+		name := flags.String("name", "", "who or what the key is for")
+		scopesFlag := flags.String("scopes", model.ScopeRead, "comma separated scopes: read, write, admin")
+		expiresIn := flags.Duration("expires-in", 0, "how long the key is valid, it never expires when 0")
+		flags.Parse(args[1:])
+
+		if *name == "" || flags.NArg() != 0 {
+			logger.Fatal(keysUsage)
+		}
+
+		scopes, err := auth.ParseScopes(*scopesFlag)
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		var expiresAt *time.Time
+		if *expiresIn > 0 {
+			expires := time.Now().Add(*expiresIn).UTC()
+			expiresAt = &expires
+		}
+
+		key, apiKey, err := auth.NewAPIKey(*name, scopes, expiresAt)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		if err := store.APIKeys.Create(ctx, &apiKey); err != nil {
+			logger.Fatalf("failed to create API key: %s", err.Error())
+		}
+		logger.Infof("created API key %s, store it now, it cannot be shown again", apiKey.ID)
+
+		if err := encoder.Encode(responses.CreatedAPIKey{APIKey: mapAPIKey(apiKey), Key: key}); err != nil {
+			logger.Fatal(err)
+		}
+	case "list":
+		apiKeys, err := store.APIKeys.GetAll(ctx)
+		if err != nil {
+			logger.Fatalf("failed to list API keys: %s", err.Error())
+		}
+
+		listed := make([]responses.APIKey, 0, len(apiKeys))
+		for _, apiKey := range apiKeys {
+			listed = append(listed, mapAPIKey(apiKey))
+		}
+
+		if err := encoder.Encode(listed); err != nil {
+			logger.Fatal(err)
+		}
+	case "revoke":
+		if len(args) != 2 {
+			logger.Fatal(keysUsage)
+		}
+
+		if err := store.APIKeys.Delete(ctx, args[1]); err != nil {
+			logger.Fatalf("failed to revoke API key %s: %s", args[1], err.Error())
+		}
+		logger.Infof("revoked API key %s", args[1])
+	default:
+		logger.Fatal(keysUsage)
+	}
+}
+
+func mapAPIKey(apiKey model.APIKey) responses.APIKey {
+	return responses.APIKey{
+		ID:        apiKey.ID,
+		Name:      apiKey.Name,
+		Scopes:    apiKey.Scopes,
+		ExpiresAt: apiKey.ExpiresAt,
+		CreatedAt: apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/requests"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"net/http"
+)
+
+// LookupBanks godoc
+//
+//	@Summary		Looks up many banks by SWIFT code
+//	@Description	Looks up up to 1000 SWIFT codes in one request. Codes are normalized like in GET /swift-codes/{swift-code}, BIC8 resolves to its XXX headquarter form. Resolved codes are keyed by the requested form and found banks by the resolved code, resolved codes without a bank are listed as unknown and malformed ones as invalid, in the order of the request.
+//	@Tags			banks
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		requests.LookupPayload	true	"SWIFT codes"
+//	@Success		200		{object}	responses.Lookup
+//	@Failure		400		{object}	responses.Problem
+//	@Failure		500		{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/lookup [post]
+func (app *application) lookupBanksHandler(w http.ResponseWriter, r *http.Request) {
+	var payload requests.LookupPayload
+	if err := readJSON(w, r, &payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if err := Validate.Struct(payload); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	lookup := responses.Lookup{
+		Resolved: make(map[string]string),
+		Found:    make(map[string]responses.ExportedBank),
+		Unknown:  []string{},
+		Invalid:  []responses.InvalidSWIFTCode{},
+	}
+
+	var swiftCodes []string
+	seen := make(map[string]bool)
+	for _, code := range payload.SWIFTCodes {
+		swiftCode, err := normalizeSwiftCode(code)
+		if err != nil {
+			lookup.Invalid = append(lookup.Invalid, responses.InvalidSWIFTCode{SWIFTCode: code, Reason: err.Error()})
+			continue
+		}
+		lookup.Resolved[code] = swiftCode
+
+		if !seen[swiftCode] {
+			seen[swiftCode] = true
+			swiftCodes = append(swiftCodes, swiftCode)
+		}
+	}
+
+	ctx := r.Context()
+
+	banks, err := app.store.Banks.GetAllBySWIFTCodes(ctx, swiftCodes)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	for _, bank := range banks {
+		lookup.Found[bank.SWIFTCode] = mapBankToExportedBank(bank)
+	}
+
+	for _, swiftCode := range swiftCodes {
+		if _, ok := lookup.Found[swiftCode]; !ok {
+			lookup.Unknown = append(lookup.Unknown, swiftCode)
+		}
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, lookup); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLookupBanksHandler(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("should return found, unknown and invalid codes", func(t *testing.T) {
+		payload := `{"swiftCodes": ["abcdplpwxxx", "MISSPLPWXXX", " ABCD PLPW 123 ", "ABCDPLPW", "TOOSHRT"]}`
+		lookup := lookupBanks(t, app.config.apiVersion, mux, payload, http.StatusOK)
+
+		if len(lookup.Found) != 2 {
+			t.Errorf("expected 2 found banks, got %d", len(lookup.Found))
+		}
+		if bank, ok := lookup.Found["ABCDPLPWXXX"]; !ok || !bank.IsHeadquarter {
+			t.Errorf("expected headquarter ABCDPLPWXXX to be found, got %+v", lookup.Found)
+		}
+		if bank, ok := lookup.Found["ABCDPLPW123"]; !ok || bank.HeadquarterSWIFTCode == nil || *bank.HeadquarterSWIFTCode != "ABCDPLPWXXX" {
+			t.Errorf("expected branch ABCDPLPW123 of ABCDPLPWXXX to be found, got %+v", lookup.Found)
+		}
+
+		if !slices.Equal(lookup.Unknown, []string{"MISSPLPWXXX"}) {
+			t.Errorf("expected MISSPLPWXXX to be unknown, got %v", lookup.Unknown)
+		}
+
+		if len(lookup.Invalid) != 1 || lookup.Invalid[0].SWIFTCode != "TOOSHRT" {
+			t.Errorf("expected TOOSHRT to be invalid, got %+v", lookup.Invalid)
+		}
+
+		expectedResolved := map[string]string{
+			"abcdplpwxxx":     "ABCDPLPWXXX",
+			"MISSPLPWXXX":     "MISSPLPWXXX",
+			" ABCD PLPW 123 ": "ABCDPLPW123",
+			"ABCDPLPW":        "ABCDPLPWXXX",
+		}
+		if !maps.Equal(lookup.Resolved, expectedResolved) {
+			t.Errorf("expected resolved codes %v, got %v", expectedResolved, lookup.Resolved)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		lookupBanks(t, app.config.apiVersion, mux, `{"swiftCodes": []}`, http.StatusBadRequest)
+	})
+
+	t.Run("too many codes", func(t *testing.T) {
+		codes := make([]string, 1001)
+		for i := range codes {
+			codes[i] = fmt.Sprintf(`"ABCDPLPW%03d"`, i)
+		}
+		payload := `{"swiftCodes": [` + strings.Join(codes, ",") + `]}`
+
+		lookupBanks(t, app.config.apiVersion, mux, payload, http.StatusBadRequest)
+	})
+}
+
+func lookupBanks(t *testing.T, apiVersion string, mux http.Handler, payload string, expectedCode int) responses.Lookup {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, apiVersion+"/swift-codes/lookup", strings.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := executeRequest(req, mux)
+	checkResponseCode(t, expectedCode, rec.Code)
+
+	var lookup responses.Lookup
+	if expectedCode == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&lookup); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.Lookup: %v", err)
+		}
+	}
+
+	return lookup
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"net/http"
+	"strconv"
+)
+
+var (
+	errInvalidLimit  = errors.New("invalid limit")
+	errInvalidCursor = errors.New("invalid cursor")
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// reads limit and cursor query params, cursor is an opaque token returned
+// as nextCursor by the previous page. Without both of them the page has zero
+// Limit and holds all banks, as responses did before paging was added.
+func parsePage(r *http.Request) (store.Page, error) {
+	page := store.Page{}
+	if r.URL.Query().Has("limit") || r.URL.Query().Has("cursor") {
+		page.Limit = defaultPageLimit
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil || parsedLimit < 1 || parsedLimit > maxPageLimit {
+			return page, fmt.Errorf("%w, it has to be a number between 1 and %d", errInvalidLimit, maxPageLimit)
+		}
+		page.Limit = parsedLimit
+	}
+
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return page, err
+		}
+		page.After = after
+	}
+
+	return page, nil
+}
+
+func encodeCursor(swiftCode string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(swiftCode))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(decoded) == 0 || len(decoded) > 11 {
+		return "", errInvalidCursor
+	}
+
+	return string(decoded), nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/bic"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"net/http"
+)
+
+// ParseSWIFTCode godoc
+//
+//	@Summary		Parses a SWIFT code
+//	@Description	Splits a SWIFT code into its ISO 9362 parts without looking it up in the database. Test and training codes have 0, passive participants 1 as the second location character.
+//	@Tags			banks
+//	@Accept			json
+//	@Produce		json
+//	@Param			swift-code	path		string	true	"SWIFT Code"
+//	@Success		200			{object}	responses.ParsedSWIFTCode
+//	@Failure		400			{object}	responses.Problem
+//	@Failure		500			{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/{swift-code}/parse [get]
+func (app *application) parseSWIFTCodeHandler(w http.ResponseWriter, r *http.Request) {
+	swiftCode, err := parseSwiftCode(w, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	code, err := bic.Parse(swiftCode)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	parsed := responses.ParsedSWIFTCode{
+		SWIFTCode:            code.String(),
+		InstitutionCode:      code.InstitutionCode,
+		CountryCode:          code.CountryCode,
+		LocationCode:         code.LocationCode,
+		BranchCode:           code.BranchCode,
+		IsHeadquarter:        code.IsHeadquarter(),
+		IsTestCode:           code.IsTest(),
+		IsPassiveParticipant: code.IsPassiveParticipant(),
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, parsed); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
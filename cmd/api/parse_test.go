@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"net/http"
+	"testing"
+)
+
+func TestParseSWIFTCodeHandler(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	tests := []struct {
+		name                 string
+		swiftCode            string
+		expected             responses.ParsedSWIFTCode
+		expectedResponseCode int
+	}{
+		{
+			name:      "should parse headquarter code",
+			swiftCode: "abcdplpwxxx",
+			expected: responses.ParsedSWIFTCode{
+				SWIFTCode:       "ABCDPLPWXXX",
+				InstitutionCode: "ABCD",
+				CountryCode:     "PL",
+				LocationCode:    "PW",
+				BranchCode:      "XXX",
+				IsHeadquarter:   true,
+			},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			name:      "should flag test code",
+			swiftCode: "ABCDPLP0123",
+			expected: responses.ParsedSWIFTCode{
+				SWIFTCode:       "ABCDPLP0123",
+				InstitutionCode: "ABCD",
+				CountryCode:     "PL",
+				LocationCode:    "P0",
+				BranchCode:      "123",
+				IsTestCode:      true,
+			},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			name:      "should flag passive participant code",
+			swiftCode: "ABCDPLP1XXX",
+			expected: responses.ParsedSWIFTCode{
+				SWIFTCode:            "ABCDPLP1XXX",
+				InstitutionCode:      "ABCD",
+				CountryCode:          "PL",
+				LocationCode:         "P1",
+				BranchCode:           "XXX",
+				IsHeadquarter:        true,
+				IsPassiveParticipant: true,
+			},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			name:                 "unknown country code",
+			swiftCode:            "ABCDQQPWXXX",
+			expectedResponseCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "location code starting with 0",
+			swiftCode:            "ABCDPL0WXXX",
+			expectedResponseCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "branch code starting with X",
+			swiftCode:            "ABCDPLPWX12",
+			expectedResponseCode: http.StatusBadRequest,
+		},
+		{
+			name:      "should resolve BIC8 to headquarter code",
+			swiftCode: "abcdplpw",
+			expected: responses.ParsedSWIFTCode{
+				SWIFTCode:       "ABCDPLPWXXX",
+				InstitutionCode: "ABCD",
+				CountryCode:     "PL",
+				LocationCode:    "PW",
+				BranchCode:      "XXX",
+				IsHeadquarter:   true,
+			},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			name:                 "invalid length",
+			swiftCode:            "ABCDPLPW1",
+			expectedResponseCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/"+tt.swiftCode+"/parse", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := executeRequest(req, mux)
+			checkResponseCode(t, tt.expectedResponseCode, rec.Code)
+
+			if tt.expectedResponseCode != http.StatusOK {
+				return
+			}
+
+			var parsed responses.ParsedSWIFTCode
+			if err := json.NewDecoder(rec.Body).Decode(&parsed); err != nil {
+				t.Fatalf("cannot unmarshal response to expected response.ParsedSWIFTCode: %v", err)
+			}
+
+			if parsed != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, parsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/auth"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/bic"
+	dbPkg "github.com/Ditta1337/RemitlyInternshipTask2025/internal/db"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+const problemContentType = "application/problem+json"
+
+// problemCodes are the stable codes sent to clients for known errors, the first match wins.
+// Clients rely on them, so codes are never renamed, only added.
+var problemCodes = []struct {
+	err  error
+	code string
+}{
+	{bic.ErrInvalidLength, "swift_code_invalid_length"},
+	{bic.ErrInvalidInstitutionCode, "institution_code_invalid"},
+	{bic.ErrInvalidCountryCode, "country_code_invalid"},
+	{bic.ErrInvalidLocationCode, "location_code_invalid"},
+	{bic.ErrInvalidBranchCode, "branch_code_invalid"},
+	{bic.ErrCountryMismatch, "swift_code_country_mismatch"},
+	{errHeadquarterMismatch, "headquarter_mismatch"},
+	{dbPkg.ErrInvalidCountry, "country_invalid"},
+	{errInvalidCountryISO2Length, "country_iso2_invalid_length"},
+	{errInvalidLimit, "limit_invalid"},
+	{errInvalidCursor, "cursor_invalid"},
+	{errInvalidAsOf, "as_of_invalid"},
+	{errMissingSearchText, "search_text_missing"},
+	{errSearchTextTooLong, "search_text_too_long"},
+	{errUnsupportedExportFormat, "export_format_unsupported"},
+	{errUnsupportedFileFormat, "file_format_unsupported"},
+	{errInvalidUpload, "upload_invalid"},
+	{errMissingFile, "file_missing"},
+	{errInvalidParameter, "parameter_invalid"},
+	{dbPkg.ErrInvalidFile, "file_invalid"},
+	{dbPkg.ErrEmptyRelease, "release_empty"},
+
+	{errBankNotFound, "bank_not_found"},
+	{errNoBanksInCountry, "country_has_no_banks"},
+	{errCountryNotFound, "country_not_found"},
+	{errInstitutionNotFound, "institution_not_found"},
+	{store.ErrNotFound, "not_found"},
+
+	{errBankAlreadyExists, "bank_already_exists"},
+	{store.ErrAlreadyExists, "already_exists"},
+	{store.ErrConflict, "concurrent_modification"},
+
+	{errMissingCredentials, "credentials_missing"},
+	{errInvalidAPIKey, "api_key_invalid"},
+	{errBearerDisabled, "bearer_token_not_accepted"},
+	{auth.ErrInvalidToken, "token_invalid"},
+	{errMissingScope, "scope_missing"},
+}
+
+// problem describes an error response, see responses.Problem
+type problem struct {
+	status int
+	code   string
+	detail string
+	errors []responses.FieldError
+	report *responses.ImportReport
+}
+
+func (app *application) writeProblem(w http.ResponseWriter, r *http.Request, p problem) {
+	body := responses.Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(p.status),
+		Status:    p.status,
+		Detail:    p.detail,
+		Instance:  r.URL.Path,
+		Code:      p.code,
+		RequestID: middleware.GetReqID(r.Context()),
+		Errors:    p.errors,
+		Report:    p.report,
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(p.status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		app.logger.Errorf("error writing problem: %s", err.Error())
+	}
+}
+
+// problemFromError describes a client error, fallbackCode is used for errors without a code of their own
+func problemFromError(status int, fallbackCode string, err error) problem {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return problem{
+			status: status,
+			code:   "validation_failed",
+			detail: "request body failed validation",
+			errors: mapValidationErrors(validationErrs),
+		}
+	}
+
+	if p, ok := problemFromDecodeError(status, err); ok {
+		return p
+	}
+
+	for _, known := range problemCodes {
+		if errors.Is(err, known.err) {
+			return problem{status: status, code: known.code, detail: err.Error()}
+		}
+	}
+
+	return problem{status: status, code: fallbackCode, detail: err.Error()}
+}
+
+// rewrites errors of readJSON, the decoder messages name Go types
+func problemFromDecodeError(status int, err error) (problem, bool) {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return problem{status: status, code: "body_too_large", detail: fmt.Sprintf("request body is larger than %d bytes", maxBytesErr.Limit)}, true
+	case errors.Is(err, io.EOF):
+		return problem{status: status, code: "body_missing", detail: "request body is empty"}, true
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return problem{status: status, code: "body_invalid_json", detail: "request body is not valid JSON, it ends unexpectedly"}, true
+	case errors.As(err, &syntaxErr):
+		return problem{status: status, code: "body_invalid_json", detail: fmt.Sprintf("request body is not valid JSON at byte %d", syntaxErr.Offset)}, true
+	case errors.As(err, &typeErr) && typeErr.Field == "":
+		return problem{status: status, code: "body_invalid_json", detail: "request body has to be of type " + jsonTypeName(typeErr.Type.Kind())}, true
+	case errors.As(err, &typeErr):
+		return problem{
+			status: status,
+			code:   "validation_failed",
+			detail: "request body failed validation",
+			errors: []responses.FieldError{{
+				Field:  typeErr.Field,
+				Rule:   "type",
+				Param:  jsonTypeName(typeErr.Type.Kind()),
+				Detail: "has to be of type " + jsonTypeName(typeErr.Type.Kind()),
+			}},
+		}, true
+	}
+
+	// the decoder has no error type for unknown fields
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		field = strings.Trim(field, `"`)
+		return problem{
+			status: status,
+			code:   "validation_failed",
+			detail: "request body failed validation",
+			errors: []responses.FieldError{{Field: field, Rule: "unknown", Detail: "is not a known field"}},
+		}, true
+	}
+
+	return problem{}, false
+}
+
+// names Go kinds the way JSON calls them
+func jsonTypeName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return kind.String()
+	}
+}
+
+func mapValidationErrors(validationErrs validator.ValidationErrors) []responses.FieldError {
+	fieldErrors := make([]responses.FieldError, 0, len(validationErrs))
+
+	for _, fieldErr := range validationErrs {
+		fieldErrors = append(fieldErrors, responses.FieldError{
+			Field:  fieldErr.Field(),
+			Rule:   fieldErr.Tag(),
+			Param:  fieldErr.Param(),
+			Detail: validationErrorDetail(fieldErr),
+		})
+	}
+
+	return fieldErrors
+}
+
+func validationErrorDetail(fieldErr validator.FieldError) string {
+	// lengths of slices count items, of strings characters
+	unit := "characters"
+	if kind := fieldErr.Kind(); kind == reflect.Slice || kind == reflect.Array {
+		unit = "items"
+	}
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "len":
+		return fmt.Sprintf("has to be %s %s long", fieldErr.Param(), unit)
+	case "min":
+		return fmt.Sprintf("has to have at least %s %s", fieldErr.Param(), unit)
+	case "max":
+		return fmt.Sprintf("has to have at most %s %s", fieldErr.Param(), unit)
+	case "alphanum":
+		return "has to contain only letters and digits"
+	case "iso3166_1_alpha2":
+		return "has to be an ISO 3166-1 alpha-2 country code"
+	case "boolean":
+		return "has to be a boolean"
+	case "timezone":
+		return "has to be an IANA time zone, e.g. Europe/Warsaw"
+	case "oneof":
+		return "has to be one of " + strings.Join(strings.Fields(fieldErr.Param()), ", ")
+	default:
+		return fmt.Sprintf("fails the %s rule", fieldErr.Tag())
+	}
+}
+
+// routeNotFoundResponse replaces the plain text 404 of chi
+func (app *application) routeNotFoundResponse(w http.ResponseWriter, r *http.Request) {
+	app.writeProblem(w, r, problem{
+		status: http.StatusNotFound,
+		code:   "route_not_found",
+		detail: fmt.Sprintf("no route %s %s", r.Method, r.URL.Path),
+	})
+}
+
+// methodNotAllowedResponse replaces the empty 405 of chi. Paths of mounted routers match
+// every method in chi, so the Allow header is read from a flat copy of the routes.
+func (app *application) methodNotAllowedResponse(routes chi.Routes) http.HandlerFunc {
+	matcher := chi.NewRouter()
+	noop := func(http.ResponseWriter, *http.Request) {}
+
+	// the walk function never fails, so neither does the walk
+	_ = chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		matcher.MethodFunc(method, route, noop)
+		// StripSlashes serves routes ending with a slash without it
+		if trimmed := strings.TrimSuffix(route, "/"); trimmed != "" && trimmed != route {
+			matcher.MethodFunc(method, trimmed, noop)
+		}
+		return nil
+	})
+
+	methods := []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var allowed []string
+		for _, method := range methods {
+			if matcher.Match(chi.NewRouteContext(), method, r.URL.Path) {
+				allowed = append(allowed, method)
+			}
+		}
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+
+		app.writeProblem(w, r, problem{
+			status: http.StatusMethodNotAllowed,
+			code:   "method_not_allowed",
+			detail: fmt.Sprintf("method %s is not allowed, allowed methods: %s", r.Method, strings.Join(allowed, ", ")),
+		})
+	}
+}
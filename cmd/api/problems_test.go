@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProblemResponses(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedCode   string
+		expectedErrors []responses.FieldError
+	}{
+		{
+			name: "should report invalid SWIFT code length", method: http.MethodGet, path: "/swift-codes/ABCDPLPW1",
+			expectedStatus: http.StatusBadRequest, expectedCode: "swift_code_invalid_length",
+		},
+		{
+			name: "should report unknown bank", method: http.MethodGet, path: "/swift-codes/ZZZZPLPWXXX",
+			expectedStatus: http.StatusNotFound, expectedCode: "bank_not_found",
+		},
+		{
+			name: "should report unknown country", method: http.MethodGet, path: "/countries/ZZ",
+			expectedStatus: http.StatusNotFound, expectedCode: "country_not_found",
+		},
+		{
+			name: "should report invalid limit", method: http.MethodGet, path: "/swift-codes/country/PL?limit=0",
+			expectedStatus: http.StatusBadRequest, expectedCode: "limit_invalid",
+		},
+		{
+			name: "should report existing bank", method: http.MethodPost, path: "/swift-codes",
+			body:           `{"swiftCode": "ABCDPLPWXXX", "bankName": "Duplicate", "countryISO2": "PL", "isHeadquarter": true}`,
+			expectedStatus: http.StatusConflict, expectedCode: "bank_already_exists",
+		},
+		{
+			name: "should map validation errors to JSON fields", method: http.MethodPost, path: "/swift-codes",
+			body:           `{"swiftCode": "ABCD", "countryISO2": "PL", "isHeadquarter": true, "codeType": "BIC9"}`,
+			expectedStatus: http.StatusBadRequest, expectedCode: "validation_failed",
+			expectedErrors: []responses.FieldError{
+				{Field: "swiftCode", Rule: "len", Param: "11", Detail: "has to be 11 characters long"},
+				{Field: "bankName", Rule: "required", Detail: "is required"},
+				{Field: "codeType", Rule: "oneof", Param: "BIC8 BIC11", Detail: "has to be one of BIC8, BIC11"},
+			},
+		},
+		{
+			name: "should report fields of wrong type", method: http.MethodPost, path: "/swift-codes",
+			body:           `{"swiftCode": 11}`,
+			expectedStatus: http.StatusBadRequest, expectedCode: "validation_failed",
+			expectedErrors: []responses.FieldError{{Field: "swiftCode", Rule: "type", Param: "string", Detail: "has to be of type string"}},
+		},
+		{
+			name: "should report unknown fields", method: http.MethodPost, path: "/swift-codes",
+			body:           `{"swift": "ABCDPLPWXXX"}`,
+			expectedStatus: http.StatusBadRequest, expectedCode: "validation_failed",
+			expectedErrors: []responses.FieldError{{Field: "swift", Rule: "unknown", Detail: "is not a known field"}},
+		},
+		{
+			name: "should report malformed JSON", method: http.MethodPost, path: "/swift-codes",
+			body:           `{"swiftCode": `,
+			expectedStatus: http.StatusBadRequest, expectedCode: "body_invalid_json",
+		},
+		{
+			name: "should report empty body", method: http.MethodPost, path: "/swift-codes/lookup",
+			expectedStatus: http.StatusBadRequest, expectedCode: "body_missing",
+		},
+		{
+			name: "should report unknown route", method: http.MethodGet, path: "/unknown",
+			expectedStatus: http.StatusNotFound, expectedCode: "route_not_found",
+		},
+		{
+			name: "should report unknown nested route", method: http.MethodGet, path: "/swift-codes/ABCDPLPWXXX/unknown",
+			expectedStatus: http.StatusNotFound, expectedCode: "route_not_found",
+		},
+		{
+			name: "should report method not allowed", method: http.MethodPost, path: "/countries/PL",
+			expectedStatus: http.StatusMethodNotAllowed, expectedCode: "method_not_allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, app.config.apiVersion+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := executeRequest(req, mux)
+			checkResponseCode(t, tt.expectedStatus, rec.Code)
+
+			if contentType := rec.Header().Get("Content-Type"); contentType != problemContentType {
+				t.Errorf("expected content type %s, got %s", problemContentType, contentType)
+			}
+
+			var problem responses.Problem
+			if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+				t.Fatalf("cannot unmarshal response to expected responses.Problem: %v", err)
+			}
+
+			if problem.Code != tt.expectedCode {
+				t.Errorf("expected code %s, got %s (%s)", tt.expectedCode, problem.Code, problem.Detail)
+			}
+			if problem.Status != tt.expectedStatus || problem.Title != http.StatusText(tt.expectedStatus) || problem.Type != "about:blank" {
+				t.Errorf("unexpected problem %+v", problem)
+			}
+			if problem.Detail == "" || problem.Instance != strings.Split(app.config.apiVersion+tt.path, "?")[0] {
+				t.Errorf("expected detail and instance, got %+v", problem)
+			}
+			if !reflect.DeepEqual(problem.Errors, tt.expectedErrors) {
+				t.Errorf("expected errors %+v, got %+v", tt.expectedErrors, problem.Errors)
+			}
+		})
+	}
+
+	t.Run("should list allowed methods", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusMethodNotAllowed, rec.Code)
+
+		if allow := rec.Header().Get("Allow"); allow != "GET, PUT, PATCH, DELETE" {
+			t.Errorf("expected Allow header GET, PUT, PATCH, DELETE, got %q", allow)
+		}
+	})
+
+	t.Run("should include request ID", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/ZZZZPLPWXXX", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Request-Id", "test-request")
+
+		rec := executeRequest(req, mux)
+
+		var problem responses.Problem
+		if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+			t.Fatal(err)
+		}
+		if problem.RequestID != "test-request" {
+			t.Errorf("expected request ID test-request, got %q", problem.RequestID)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/ratelimit"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// rateLimit takes a token from the bucket of the client for the class of the route,
+// read or write, or ip before authentication. Authenticated clients are limited by their
+// API key or token subject, others by their IP, set by middleware.RealIP.
+func (app *application) rateLimit(class string, limit ratelimit.Limit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if app.limiter == nil || limit.Unlimited() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := app.limiter.Allow(r.Context(), class+"/"+rateLimitClient(r), limit)
+			if err != nil {
+				// an unavailable limiter should not take the API down with it
+				app.requestLogger(r).Errorf("rate limiter failed: %s, path: %s, error: %s", r.Method, r.URL.Path, err.Error())
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, seconds(limit.Period)))
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+
+			if !result.Allowed {
+				app.rateLimitExceededResponse(w, r, result.RetryAfter)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func rateLimitClient(r *http.Request) string {
+	if p, ok := principalFromContext(r.Context()); ok {
+		return "subject:" + p.subject
+	}
+
+	// RealIP leaves the port of RemoteAddr when there is no forwarding header
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	return "ip:" + ip
+}
+
+// whole seconds, rounded up so clients do not retry too early
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/auth"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/ratelimit"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	newApp := func(t *testing.T) *application {
+		t.Helper()
+
+		app := newMockApplication(t)
+		app.limiter = ratelimit.NewMemoryLimiter()
+		app.config.rateLimit = rateLimitConfig{
+			read:  ratelimit.Limit{Requests: 2, Period: time.Minute},
+			write: ratelimit.Limit{Requests: 1, Period: time.Minute},
+		}
+
+		return app
+	}
+
+	request := func(t *testing.T, mux http.Handler, method, path, remoteAddr, key string) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = remoteAddr
+		if key != "" {
+			req.Header.Set(apiKeyHeader, key)
+		}
+
+		return executeRequest(req, mux).Result()
+	}
+
+	t.Run("should limit reads by client IP", func(t *testing.T) {
+		app := newApp(t)
+		mux := app.mount()
+		path := app.config.apiVersion + "/swift-codes/ABCDPLPWXXX"
+
+		for i := range 2 {
+			res := request(t, mux, http.MethodGet, path, "192.0.2.1:1234", "")
+			checkResponseCode(t, http.StatusOK, res.StatusCode)
+			if res.Header.Get("RateLimit-Limit") != "2" || res.Header.Get("RateLimit-Remaining") != []string{"1", "0"}[i] {
+				t.Errorf("unexpected RateLimit headers: %v", res.Header)
+			}
+		}
+
+		res := request(t, mux, http.MethodGet, path, "192.0.2.1:4321", "")
+		checkResponseCode(t, http.StatusTooManyRequests, res.StatusCode)
+		if res.Header.Get("Retry-After") != "30" {
+			t.Errorf("expected Retry-After 30, got %q", res.Header.Get("Retry-After"))
+		}
+		if res.Header.Get("RateLimit-Reset") != "60" || res.Header.Get("RateLimit-Policy") != "2;w=60" {
+			t.Errorf("unexpected RateLimit headers: %v", res.Header)
+		}
+
+		var body responses.Problem
+		if err := json.NewDecoder(res.Body).Decode(&body); err != nil || body.Code != "rate_limit_exceeded" {
+			t.Errorf("expected rate_limit_exceeded problem, got %+v, %v", body, err)
+		}
+
+		res = request(t, mux, http.MethodGet, path, "192.0.2.2:1234", "")
+		checkResponseCode(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("should limit reads and writes separately", func(t *testing.T) {
+		app := newApp(t)
+		mux := app.mount()
+
+		checkResponseCode(t, http.StatusOK, request(t, mux, http.MethodDelete, app.config.apiVersion+"/swift-codes/ABCDPLPW123", "192.0.2.1:1234", "").StatusCode)
+		checkResponseCode(t, http.StatusTooManyRequests, request(t, mux, http.MethodDelete, app.config.apiVersion+"/swift-codes/ABCDPLPW123", "192.0.2.1:1234", "").StatusCode)
+		checkResponseCode(t, http.StatusOK, request(t, mux, http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", "192.0.2.1:1234", "").StatusCode)
+	})
+
+	t.Run("should limit by API key", func(t *testing.T) {
+		app := newApp(t)
+		app.config.auth.enabled = true
+		mux := app.mount()
+
+		newKey := func(t *testing.T) string {
+			t.Helper()
+
+			key, apiKey, err := auth.NewAPIKey("test", []string{model.ScopeRead}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := app.store.APIKeys.Create(context.Background(), &apiKey); err != nil {
+				t.Fatal(err)
+			}
+
+			return key
+		}
+
+		firstKey := newKey(t)
+		secondKey := newKey(t)
+		path := app.config.apiVersion + "/countries/PL"
+
+		// the same key from different IPs shares one bucket
+		checkResponseCode(t, http.StatusOK, request(t, mux, http.MethodGet, path, "192.0.2.1:1234", firstKey).StatusCode)
+		checkResponseCode(t, http.StatusOK, request(t, mux, http.MethodGet, path, "192.0.2.2:1234", firstKey).StatusCode)
+		checkResponseCode(t, http.StatusTooManyRequests, request(t, mux, http.MethodGet, path, "192.0.2.3:1234", firstKey).StatusCode)
+
+		// another key from the same IP has its own bucket
+		checkResponseCode(t, http.StatusOK, request(t, mux, http.MethodGet, path, "192.0.2.1:1234", secondKey).StatusCode)
+	})
+
+	t.Run("should limit failed authentications by IP", func(t *testing.T) {
+		app := newApp(t)
+		app.config.auth.enabled = true
+		app.config.rateLimit.ip = ratelimit.Limit{Requests: 2, Period: time.Minute}
+		mux := app.mount()
+		path := app.config.apiVersion + "/countries/PL"
+
+		checkResponseCode(t, http.StatusUnauthorized, request(t, mux, http.MethodGet, path, "192.0.2.1:1234", "").StatusCode)
+		checkResponseCode(t, http.StatusUnauthorized, request(t, mux, http.MethodGet, path, "192.0.2.1:1234", "invalid-key").StatusCode)
+
+		res := request(t, mux, http.MethodGet, path, "192.0.2.1:1234", "invalid-key")
+		checkResponseCode(t, http.StatusTooManyRequests, res.StatusCode)
+		if res.Header.Get("RateLimit-Policy") != "2;w=60" {
+			t.Errorf("unexpected RateLimit headers: %v", res.Header)
+		}
+
+		checkResponseCode(t, http.StatusUnauthorized, request(t, mux, http.MethodGet, path, "192.0.2.2:1234", "invalid-key").StatusCode)
+	})
+
+	t.Run("should not limit without a limiter", func(t *testing.T) {
+		app := newMockApplication(t)
+		mux := app.mount()
+
+		for range 5 {
+			res := request(t, mux, http.MethodGet, app.config.apiVersion+"/swift-codes/ABCDPLPWXXX", "192.0.2.1:1234", "")
+			checkResponseCode(t, http.StatusOK, res.StatusCode)
+			if res.Header.Get("RateLimit-Limit") != "" {
+				t.Errorf("expected no RateLimit headers, got %v", res.Header)
+			}
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errMissingSearchText = errors.New("missing search text")
+	errSearchTextTooLong = errors.New("search text too long")
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchBanks godoc
+//
+//	@Summary		Searches banks
+//	@Description	Searches banks by name, town and address. Matching ignores case and accents and tolerates typos, best matches come first.
+//	@Tags			banks
+//	@Accept			json
+//	@Produce		json
+//	@Param			q					query		string	true	"Searched text"
+//	@Param			country				query		string	false	"Country ISO2 Code"
+//	@Param			headquartersOnly	query		bool	false	"Search only headquarters"
+//	@Param			limit				query		int		false	"Maximum number of results"	minimum(1)	maximum(100)	default(20)
+//	@Success		200					{object}	responses.SearchResults
+//	@Failure		400					{object}	responses.Problem
+//	@Failure		500					{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Security		BearerAuth
+//	@Router			/swift-codes/search [get]
+func (app *application) searchBanksHandler(w http.ResponseWriter, r *http.Request) {
+	search, err := parseSearchQuery(r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	results, err := app.store.Banks.Search(ctx, search)
+	if err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+
+	searchResults := responses.SearchResults{
+		Query:   search.Text,
+		Results: mapSearchResults(results),
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, searchResults); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+func parseSearchQuery(r *http.Request) (store.SearchQuery, error) {
+	search := store.SearchQuery{
+		Text:  strings.TrimSpace(r.URL.Query().Get("q")),
+		Limit: defaultSearchLimit,
+	}
+
+	if search.Text == "" {
+		return search, errMissingSearchText
+	}
+	if len(search.Text) > 255 {
+		return search, errSearchTextTooLong
+	}
+
+	if country := r.URL.Query().Get("country"); country != "" {
+		search.CountryISO2 = strings.ToUpper(country)
+		if len(search.CountryISO2) != 2 {
+			return search, errInvalidCountryISO2Length
+		}
+	}
+
+	headquartersOnly, err := parseBoolParam(r, "headquartersOnly")
+	if err != nil {
+		return search, err
+	}
+	search.HeadquartersOnly = headquartersOnly
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil || parsedLimit < 1 || parsedLimit > maxSearchLimit {
+			return search, fmt.Errorf("%w, it has to be a number between 1 and %d", errInvalidLimit, maxSearchLimit)
+		}
+		search.Limit = parsedLimit
+	}
+
+	return search, nil
+}
+
+func mapSearchResults(results []model.SearchResult) []responses.SearchResult {
+	searchResults := make([]responses.SearchResult, 0, len(results))
+
+	for _, result := range results {
+		searchResults = append(searchResults, responses.SearchResult{
+			SWIFTCode:     result.Bank.SWIFTCode,
+			BankName:      result.Bank.BankName,
+			Address:       result.Bank.Address,
+			TownName:      result.Bank.TownName,
+			CountryISO2:   result.Bank.CountryISO2,
+			CountryName:   result.Bank.CountryName,
+			IsHeadquarter: result.Bank.IsHeadquarter,
+			Score:         result.Score,
+		})
+	}
+
+	return searchResults
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSearchBanksHandler(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	tests := []struct {
+		name                 string
+		query                url.Values
+		expectedSWIFTCodes   []string
+		expectedResponseCode int
+	}{
+		{
+			name:                 "should tolerate typos in bank name",
+			query:                url.Values{"q": {"headqarter bnak"}},
+			expectedSWIFTCodes:   []string{"ABCDPLPWXXX"},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			name:                 "should fold accents and case of town name",
+			query:                url.Values{"q": {"wąrszawa"}},
+			expectedSWIFTCodes:   []string{"ABCDPLPW123", "ABCDPLPWXXX"},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			name:                 "should search only headquarters",
+			query:                url.Values{"q": {"warszawa"}, "headquartersOnly": {"true"}},
+			expectedSWIFTCodes:   []string{"ABCDPLPWXXX"},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			name:                 "should search only given country",
+			query:                url.Values{"q": {"warszawa"}, "country": {"DE"}},
+			expectedSWIFTCodes:   []string{},
+			expectedResponseCode: http.StatusOK,
+		},
+		{
+			name:                 "missing search text",
+			query:                url.Values{"country": {"PL"}},
+			expectedResponseCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, app.config.apiVersion+"/swift-codes/search?"+tt.query.Encode(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rec := executeRequest(req, mux)
+			checkResponseCode(t, tt.expectedResponseCode, rec.Code)
+
+			if tt.expectedResponseCode != http.StatusOK {
+				return
+			}
+
+			var response responses.SearchResults
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("cannot unmarshal response to expected response.SearchResults: %v", err)
+			}
+
+			if len(response.Results) != len(tt.expectedSWIFTCodes) {
+				t.Fatalf("expected %d results, got %d", len(tt.expectedSWIFTCodes), len(response.Results))
+			}
+
+			for i, result := range response.Results {
+				if result.SWIFTCode != tt.expectedSWIFTCodes[i] {
+					t.Errorf("expected result %d to be %s, got %s", i, tt.expectedSWIFTCodes[i], result.SWIFTCode)
+				}
+			}
+		})
+	}
+}
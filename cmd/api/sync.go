@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	dbPkg "github.com/Ditta1337/RemitlyInternshipTask2025/internal/db"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"go.uber.org/zap"
+	"net/http"
+	"os"
+	"time"
+)
+
+// SyncBanks godoc
+//
+//	@Summary		Synchronizes banks with a new SWIFT directory release
+//	@Description	Makes the stored banks match the file: new banks are inserted, changed ones updated, banks missing from the file removed and branches re-linked to their headquarters. The file is applied in a single transaction, an invalid row rejects the whole file. The file can be sent as multipart form field "file" or as a raw request body.
+//	@Tags			admin
+//	@Accept			mpfd,plain
+//	@Produce		json
+//	@Param			file		formData	file	false	"SWIFT directory file"
+//	@Param			format		query		string	false	"File format, detected from the file name when omitted"	Enums(tsv, csv)
+//	@Param			dryRun		query		bool	false	"Only report what would change"
+//	@Param			allowEmpty	query		bool	false	"Apply a release without banks, which removes all of them"
+//	@Success		200			{object}	responses.SyncReport
+//	@Failure		400			{object}	responses.Problem
+//	@Failure		500			{object}	responses.Problem
+//	@Security		ApiKeyAuth
+//	@Router			/admin/sync [post]
+func (app *application) syncBanksHandler(w http.ResponseWriter, r *http.Request) {
+	// a whole release takes longer to upload and apply than the server timeouts
+	controller := http.NewResponseController(w)
+	_ = controller.SetReadDeadline(time.Time{})
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+
+	file, fileName, err := readUpload(r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+	defer file.Close()
+
+	comma, err := parseFileFormat(r.URL.Query().Get("format"), fileName)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	dryRun, err := parseBoolParam(r, "dryRun")
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	allowEmpty, err := parseBoolParam(r, "allowEmpty")
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	report, err := dbPkg.Sync(ctx, app.store, file, dbPkg.SyncOptions{Comma: comma, DryRun: dryRun, AllowEmpty: allowEmpty})
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, dbPkg.ErrInvalidFile), errors.Is(err, dbPkg.ErrEmptyRelease), errors.As(err, &maxBytesErr):
+			app.badRequestResponse(w, r, err)
+		case errors.Is(err, store.ErrAlreadyExists), errors.Is(err, store.ErrConflict):
+			app.conflictResponse(w, r, err)
+		default:
+			app.internalServerError(w, r, err)
+		}
+		return
+	}
+
+	if err := app.writeJSONResponse(w, http.StatusOK, mapSyncReport(report)); err != nil {
+		app.internalServerError(w, r, err)
+		return
+	}
+}
+
+// runSync is the "sync" subcommand, it applies the file to the configured database
+// and prints the change report as JSON to stdout
+func runSync(ctx context.Context, cfg config, logger *zap.SugaredLogger, args []string) {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only report what would change")
+	allowEmpty := flags.Bool("allow-empty", false, "apply a release without banks, which removes all of them")
+	format := flags.String("format", "", "file format, tsv or csv, detected from the file name when empty")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		logger.Fatal("usage: sync [--dry-run] [--allow-empty] [--format tsv|csv] <file>")
+	}
+	path := flags.Arg(0)
+
+	comma, err := parseFileFormat(*format, path)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	defer file.Close()
+
+	db, store := openDB(cfg, logger)
+	defer db.Close()
+
+	report, err := dbPkg.Sync(ctx, store, file, dbPkg.SyncOptions{
+		Comma:      comma,
+		DryRun:     *dryRun,
+		AllowEmpty: *allowEmpty,
+		Progress: func(rows int) {
+			logger.Infof("sync: read %d rows from %s", rows, path)
+		},
+	})
+	if err != nil {
+		logger.Fatalf("failed to sync: %s", err.Error())
+	}
+
+	logger.Infof(
+		"finished sync: %d inserted, %d updated, %d removed, %d unchanged, dry run: %t",
+		len(report.Inserted), len(report.Updated), len(report.Removed), report.Unchanged, report.DryRun,
+	)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(mapSyncReport(report)); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func mapSyncReport(report *dbPkg.SyncReport) responses.SyncReport {
+	changes := make([]responses.BankChange, 0, len(report.Updated))
+	for _, change := range report.Updated {
+		changes = append(changes, responses.BankChange{
+			SWIFTCode:     change.After.SWIFTCode,
+			ChangedFields: changedBankFields(change.Before, change.After),
+			Before:        mapBankToExportedBank(change.Before),
+			After:         mapBankToExportedBank(change.After),
+		})
+	}
+
+	return responses.SyncReport{
+		DryRun:    report.DryRun,
+		Inserted:  nonNilStrings(report.Inserted),
+		Updated:   changes,
+		Removed:   nonNilStrings(report.Removed),
+		Unchanged: report.Unchanged,
+	}
+}
+
+// returns JSON names of the fields that differ between the two states of a bank
+func changedBankFields(before, after model.Bank) []string {
+	var fields []string
+
+	if !equalOptional(before.Address, after.Address) {
+		fields = append(fields, "address")
+	}
+	if before.BankName != after.BankName {
+		fields = append(fields, "bankName")
+	}
+	if before.CountryISO2 != after.CountryISO2 {
+		fields = append(fields, "countryISO2")
+	}
+	if before.CountryName != after.CountryName {
+		fields = append(fields, "countryName")
+	}
+	if before.IsHeadquarter != after.IsHeadquarter {
+		fields = append(fields, "isHeadquarter")
+	}
+	if !equalOptional(before.HeadquarterSWIFTCode, after.HeadquarterSWIFTCode) {
+		fields = append(fields, "headquarterSwiftCode")
+	}
+	if !equalOptional(before.TownName, after.TownName) {
+		fields = append(fields, "townName")
+	}
+	if !equalOptional(before.TimeZone, after.TimeZone) {
+		fields = append(fields, "timeZone")
+	}
+	if !equalOptional(before.CodeType, after.CodeType) {
+		fields = append(fields, "codeType")
+	}
+
+	return fields
+}
+
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// empty lists are encoded as [] instead of null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
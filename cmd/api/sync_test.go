@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/dto/responses"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// new address of the headquarter, new german bank, polish branch withdrawn
+const syncFile = "COUNTRY ISO2 CODE\tSWIFT CODE\tCODE TYPE\tNAME\tADDRESS\tTOWN NAME\tCOUNTRY NAME\tTIME ZONE\n" +
+	"PL\tABCDPLPWXXX\t\tHeadquarter bank PL\tNew Street 1\tWARSZAWA\tPoland\tEurope/Warsaw\n" +
+	"DE\tSYNCDEFFXXX\tBIC11\tSynced bank\t\tBERLIN\tGERMANY\tEurope/Berlin\n"
+
+func TestSyncBanksHandler(t *testing.T) {
+	app := newMockApplication(t)
+	mux := app.mount()
+
+	t.Run("dry run should report without changing banks", func(t *testing.T) {
+		report := syncBanks(t, mux, app.config.apiVersion+"/admin/sync?dryRun=true", syncFile, http.StatusOK)
+		checkSyncReport(t, report)
+
+		if !report.DryRun {
+			t.Errorf("expected dry run report")
+		}
+
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/ABCDPLPW123", http.StatusOK)
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/SYNCDEFFXXX", http.StatusNotFound)
+	})
+
+	t.Run("invalid row should reject the whole file", func(t *testing.T) {
+		payload := syncFile + "DE\tSYNCDEFF123\tBIC11\tBad Time Zone\t\tBERLIN\tGERMANY\tEurope/Atlantis\n"
+		syncBanks(t, mux, app.config.apiVersion+"/admin/sync", payload, http.StatusBadRequest)
+
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/SYNCDEFFXXX", http.StatusNotFound)
+	})
+
+	t.Run("country name not matching the country should reject the whole file", func(t *testing.T) {
+		payload := syncFile + "DE\tSYNCDEFF123\tBIC11\tWrong country\t\tBERLIN\tFRANCE\tEurope/Berlin\n"
+		syncBanks(t, mux, app.config.apiVersion+"/admin/sync", payload, http.StatusBadRequest)
+
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/SYNCDEFFXXX", http.StatusNotFound)
+	})
+
+	t.Run("empty release should be rejected", func(t *testing.T) {
+		header := strings.SplitAfter(syncFile, "\n")[0]
+		syncBanks(t, mux, app.config.apiVersion+"/admin/sync", header, http.StatusBadRequest)
+
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/ABCDPLPW123", http.StatusOK)
+	})
+
+	t.Run("raw body sent as a form should not be read as the form", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, app.config.apiVersion+"/admin/sync?dryRun=true", strings.NewReader(syncFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// curl --data-binary sends this content type by default
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		rec := executeRequest(req, mux)
+		checkResponseCode(t, http.StatusOK, rec.Code)
+
+		var report responses.SyncReport
+		if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.SyncReport: %v", err)
+		}
+		checkSyncReport(t, report)
+
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/ABCDPLPW123", http.StatusOK)
+	})
+
+	t.Run("should apply release", func(t *testing.T) {
+		report := syncBanks(t, mux, app.config.apiVersion+"/admin/sync", syncFile, http.StatusOK)
+		checkSyncReport(t, report)
+
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/ABCDPLPW123", http.StatusNotFound)
+		checkBankStatus(t, mux, app.config.apiVersion+"/swift-codes/SYNCDEFFXXX", http.StatusOK)
+
+		// the release is already applied
+		report = syncBanks(t, mux, app.config.apiVersion+"/admin/sync", syncFile, http.StatusOK)
+		if len(report.Inserted) != 0 || len(report.Updated) != 0 || len(report.Removed) != 0 || report.Unchanged != 2 {
+			t.Errorf("expected no changes, got %+v", report)
+		}
+	})
+
+	t.Run("empty release should remove all banks when allowed", func(t *testing.T) {
+		header := strings.SplitAfter(syncFile, "\n")[0]
+		report := syncBanks(t, mux, app.config.apiVersion+"/admin/sync?allowEmpty=true", header, http.StatusOK)
+		if !slices.Equal(report.Removed, []string{"ABCDPLPWXXX", "SYNCDEFFXXX"}) {
+			t.Errorf("expected all banks removed, got %+v", report.Removed)
+		}
+	})
+}
+
+func syncBanks(t *testing.T, mux http.Handler, url, payload string, expectedCode int) responses.SyncReport {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "text/tab-separated-values")
+
+	rec := executeRequest(req, mux)
+	checkResponseCode(t, expectedCode, rec.Code)
+
+	var report responses.SyncReport
+	if expectedCode == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+			t.Fatalf("cannot unmarshal response to expected response.SyncReport: %v", err)
+		}
+	}
+
+	return report
+}
+
+func checkSyncReport(t *testing.T, report responses.SyncReport) {
+	t.Helper()
+
+	if !slices.Equal(report.Inserted, []string{"SYNCDEFFXXX"}) {
+		t.Errorf("expected SYNCDEFFXXX to be inserted, got %v", report.Inserted)
+	}
+
+	if !slices.Equal(report.Removed, []string{"ABCDPLPW123"}) {
+		t.Errorf("expected ABCDPLPW123 to be removed, got %v", report.Removed)
+	}
+
+	if len(report.Updated) != 1 {
+		t.Fatalf("expected 1 updated bank, got %d", len(report.Updated))
+	}
+
+	change := report.Updated[0]
+	if change.SWIFTCode != "ABCDPLPWXXX" || !slices.Equal(change.ChangedFields, []string{"address"}) {
+		t.Errorf("expected address of ABCDPLPWXXX to change, got %+v", change)
+	}
+	if change.After.Address == nil || *change.After.Address != "New Street 1" {
+		t.Errorf("expected new address in report, got %v", change.After.Address)
+	}
+}
+
+func checkBankStatus(t *testing.T, mux http.Handler, url string, expectedCode int) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := executeRequest(req, mux)
+	checkResponseCode(t, expectedCode, rec.Code)
+}
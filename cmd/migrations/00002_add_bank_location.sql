@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE banks
+    ADD COLUMN townName varchar(255) NULL,
+    ADD COLUMN timeZone varchar(64)  NULL,
+    ADD COLUMN codeType varchar(5)   NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE banks
+    DROP COLUMN codeType,
+    DROP COLUMN timeZone,
+    DROP COLUMN townName;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_country_swift_code ON banks (countryISO2, swiftCode);
+
+DROP INDEX IF EXISTS idx_country;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE INDEX idx_country ON banks (countryISO2);
+
+DROP INDEX IF EXISTS idx_country_swift_code;
+-- +goose StatementEnd
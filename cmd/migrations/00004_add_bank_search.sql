@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE, pinning the dictionary makes it usable in generated columns and indexes
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+    LANGUAGE sql
+    IMMUTABLE PARALLEL SAFE STRICT
+AS
+$$
+SELECT public.unaccent('public.unaccent', $1)
+$$;
+
+ALTER TABLE banks
+    ADD COLUMN searchText text GENERATED ALWAYS AS (
+        lower(immutable_unaccent(bankName || ' ' || coalesce(townName, '') || ' ' || coalesce(address, '')))
+        ) STORED;
+
+CREATE INDEX idx_search_text ON banks USING gin (searchText gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_search_text;
+
+ALTER TABLE banks
+    DROP COLUMN searchText;
+
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
+-- +goose StatementEnd
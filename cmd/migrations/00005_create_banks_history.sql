@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE banks_history
+(
+    id                   bigserial PRIMARY KEY,
+    swiftCode            varchar(11)  NOT NULL,
+    address              varchar(255),
+    bankName             varchar(255) NOT NULL,
+    countryISO2          varchar(2)   NOT NULL,
+    countryName          varchar(255) NOT NULL,
+    isHeadquarter        boolean      NOT NULL,
+    headquarterSwiftCode varchar(11)  NULL,
+    townName             varchar(255) NULL,
+    timeZone             varchar(64)  NULL,
+    codeType             varchar(5)   NULL,
+    operation            varchar(6)   NOT NULL,
+    validFrom            timestamptz  NOT NULL,
+    validTo              timestamptz  NULL,
+    CONSTRAINT chk_operation CHECK (operation IN ('INSERT', 'UPDATE', 'DELETE'))
+);
+
+CREATE INDEX idx_history_swift_code ON banks_history (swiftCode, validFrom);
+CREATE INDEX idx_history_headquarter ON banks_history (headquarterSwiftCode);
+CREATE INDEX idx_history_country_swift_code ON banks_history (countryISO2, swiftCode);
+-- at most one open version per SWIFT code
+CREATE UNIQUE INDEX idx_history_current ON banks_history (swiftCode) WHERE validTo IS NULL;
+
+-- history starts with the banks already stored
+INSERT INTO banks_history (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode,
+                           townName, timeZone, codeType, operation, validFrom)
+SELECT swiftCode,
+       address,
+       bankName,
+       countryISO2,
+       countryName,
+       isHeadquarter,
+       headquarterSwiftCode,
+       townName,
+       timeZone,
+       codeType,
+       'INSERT',
+       now()
+FROM banks;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS banks_history;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE countries
+(
+    iso2 varchar(2)   PRIMARY KEY,
+    -- uppercase ISO 3166 short name, as written in the SWIFT directory
+    name varchar(255) NOT NULL
+);
+-- rows are written from internal/store/countries.tsv by store.SeedCountries after the migrations
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS countries;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE banks
+    ADD COLUMN createdAt timestamptz NOT NULL DEFAULT now(),
+    ADD COLUMN updatedAt timestamptz NOT NULL DEFAULT now();
+
+-- stored banks take their timestamps from history
+UPDATE banks
+SET createdAt = coalesce((SELECT max(validFrom)
+                          FROM banks_history
+                          WHERE banks_history.swiftCode = banks.swiftCode AND operation = 'INSERT'), createdAt),
+    updatedAt = coalesce((SELECT validFrom
+                          FROM banks_history
+                          WHERE banks_history.swiftCode = banks.swiftCode AND validTo IS NULL), updatedAt);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE banks
+    DROP COLUMN IF EXISTS createdAt,
+    DROP COLUMN IF EXISTS updatedAt;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE api_keys
+(
+    id        varchar(16)  PRIMARY KEY,
+    name      varchar(255) NOT NULL,
+    -- hex encoded SHA-256 of the key, the key itself is never stored
+    keyHash   varchar(64)  NOT NULL UNIQUE,
+    -- comma separated, e.g. read,write
+    scopes    varchar(255) NOT NULL,
+    expiresAt timestamptz  NULL,
+    createdAt timestamptz  NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- subject of the API key or token that made the change, NULL for changes made without one
+ALTER TABLE banks_history ADD COLUMN changedBy varchar(255) NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE banks_history DROP COLUMN changedBy;
+-- +goose StatementEnd
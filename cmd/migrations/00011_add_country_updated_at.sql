@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- time of the latest change of a bank in the country, including removals
+ALTER TABLE countries
+    ADD COLUMN updatedAt timestamptz NOT NULL DEFAULT now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE countries
+    DROP COLUMN IF EXISTS updatedAt;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE banks
+(
+    swiftCode            varchar(11) PRIMARY KEY,
+    address              varchar(255),
+    bankName             varchar(255) NOT NULL,
+    countryISO2          varchar(2)   NOT NULL,
+    countryName          varchar(255) NOT NULL,
+    isHeadquarter        boolean      NOT NULL,
+    headquarterSwiftCode varchar(11)  NULL,
+    townName             varchar(255) NULL,
+    timeZone             varchar(64)  NULL,
+    codeType             varchar(5)   NULL,
+    CONSTRAINT fk_headquarter FOREIGN KEY (headquarterSwiftCode) REFERENCES banks (swiftCode) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_headquarter ON banks (headquarterSwiftCode);
+CREATE INDEX idx_country_swift_code ON banks (countryISO2, swiftCode);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS banks;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE banks_history
+(
+    id                   integer PRIMARY KEY AUTOINCREMENT,
+    swiftCode            varchar(11)  NOT NULL,
+    address              varchar(255),
+    bankName             varchar(255) NOT NULL,
+    countryISO2          varchar(2)   NOT NULL,
+    countryName          varchar(255) NOT NULL,
+    isHeadquarter        boolean      NOT NULL,
+    headquarterSwiftCode varchar(11)  NULL,
+    townName             varchar(255) NULL,
+    timeZone             varchar(64)  NULL,
+    codeType             varchar(5)   NULL,
+    operation            varchar(6)   NOT NULL,
+    -- fixed width UTC timestamps, e.g. 2025-01-01T00:00:00.000000000Z
+    validFrom            text         NOT NULL,
+    validTo              text         NULL,
+    CONSTRAINT chk_operation CHECK (operation IN ('INSERT', 'UPDATE', 'DELETE'))
+);
+
+CREATE INDEX idx_history_swift_code ON banks_history (swiftCode, validFrom);
+CREATE INDEX idx_history_headquarter ON banks_history (headquarterSwiftCode);
+CREATE INDEX idx_history_country_swift_code ON banks_history (countryISO2, swiftCode);
+-- at most one open version per SWIFT code
+CREATE UNIQUE INDEX idx_history_current ON banks_history (swiftCode) WHERE validTo IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS banks_history;
+-- +goose StatementEnd
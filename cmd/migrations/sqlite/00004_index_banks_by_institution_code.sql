@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the first four characters of a SWIFT code identify the institution
+CREATE INDEX idx_institution_code_swift_code ON banks (substr(swiftCode, 1, 4), swiftCode);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_institution_code_swift_code;
+-- +goose StatementEnd
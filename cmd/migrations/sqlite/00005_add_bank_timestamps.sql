@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- added columns cannot default to the current time, banks are stamped when their history is recorded
+ALTER TABLE banks ADD COLUMN createdAt text NOT NULL DEFAULT '';
+ALTER TABLE banks ADD COLUMN updatedAt text NOT NULL DEFAULT '';
+
+-- stored banks take their timestamps from history
+UPDATE banks
+SET createdAt = coalesce((SELECT max(validFrom)
+                          FROM banks_history
+                          WHERE banks_history.swiftCode = banks.swiftCode AND operation = 'INSERT'),
+                         strftime('%Y-%m-%dT%H:%M:%S.000000000Z', 'now')),
+    updatedAt = coalesce((SELECT validFrom
+                          FROM banks_history
+                          WHERE banks_history.swiftCode = banks.swiftCode AND validTo IS NULL),
+                         strftime('%Y-%m-%dT%H:%M:%S.000000000Z', 'now'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE banks DROP COLUMN createdAt;
+ALTER TABLE banks DROP COLUMN updatedAt;
+-- +goose StatementEnd
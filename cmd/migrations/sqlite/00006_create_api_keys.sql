@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE api_keys
+(
+    id        varchar(16)  PRIMARY KEY,
+    name      varchar(255) NOT NULL,
+    -- hex encoded SHA-256 of the key, the key itself is never stored
+    keyHash   varchar(64)  NOT NULL UNIQUE,
+    -- comma separated, e.g. read,write
+    scopes    varchar(255) NOT NULL,
+    -- fixed width UTC timestamps, e.g. 2025-01-01T00:00:00.000000000Z
+    expiresAt text         NULL,
+    createdAt text         NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- time of the latest change of a bank in the country, including removals.
+-- Added columns cannot default to the current time, countries seeded later stay empty until a bank changes.
+ALTER TABLE countries ADD COLUMN updatedAt text NOT NULL DEFAULT '';
+
+UPDATE countries
+SET updatedAt = strftime('%Y-%m-%dT%H:%M:%S.000000000Z', 'now');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE countries DROP COLUMN updatedAt;
+-- +goose StatementEnd
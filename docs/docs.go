@@ -19,55 +19,113 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/swift-codes": {
+        "/admin/cache": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Counts hits, misses, evictions and invalidations of the in-process cache of bank lookups since the start of the server. Counters are zero when the cache is disabled with STORE_CACHE_SIZE=0.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Gets storage cache counters",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CacheStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sync": {
             "post": {
-                "description": "Creates a bank",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Makes the stored banks match the file: new banks are inserted, changed ones updated, banks missing from the file removed and branches re-linked to their headquarters. The file is applied in a single transaction, an invalid row rejects the whole file. The file can be sent as multipart form field \"file\" or as a raw request body.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data",
+                    "text/plain"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "banks"
+                    "admin"
                 ],
-                "summary": "Creates a bank",
+                "summary": "Synchronizes banks with a new SWIFT directory release",
                 "parameters": [
                     {
-                        "description": "Bank payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.BankPayload"
-                        }
+                        "type": "file",
+                        "description": "SWIFT directory file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "tsv",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "File format, detected from the file name when omitted",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would change",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apply a release without banks, which removes all of them",
+                        "name": "allowEmpty",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.Message"
+                            "$ref": "#/definitions/responses.SyncReport"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     }
                 }
             }
         },
-        "/swift-codes/country/{countryISO2code}": {
+        "/countries": {
             "get": {
-                "description": "Gets all banks with given Country ISO2 Code",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets all ISO 3166 countries ordered by ISO2 code, with canonical names and numbers of stored banks and headquarters",
                 "consumes": [
                     "application/json"
                 ],
@@ -75,14 +133,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "banks"
+                    "countries"
                 ],
-                "summary": "Gets all banks with given Country ISO2 Code",
+                "summary": "Gets all countries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Countries"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/countries/{iso2}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets the canonical name of the country and numbers of its stored banks and headquarters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "countries"
+                ],
+                "summary": "Gets a country by ISO2 code",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Country ISO2 Code",
-                        "name": "countryISO2code",
+                        "name": "iso2",
                         "in": "path",
                         "required": true
                     }
@@ -91,27 +186,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.AllBanks"
+                            "$ref": "#/definitions/responses.Country"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     }
                 }
             }
         },
-        "/swift-codes/{swift-code}": {
+        "/institutions/{code}": {
             "get": {
-                "description": "Gets a bank by SWIFT code",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets every headquarter sharing the 4 character institution code of SWIFT codes, with their branches, and the countries the institution has banks in",
                 "consumes": [
                     "application/json"
                 ],
@@ -119,45 +228,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "banks"
+                    "institutions"
                 ],
-                "summary": "Gets a bank by SWIFT code",
+                "summary": "Gets banks of an institution in every country",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SWIFT Code",
-                        "name": "swift-code",
+                        "description": "Institution code, the first 4 characters of SWIFT codes",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns either a BankHeadquarter or BankBranch. See the API documentation for details.",
-                        "schema": {}
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Institution"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a bank by SWIFT code",
+            }
+        },
+        "/swift-codes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a bank. The country name is filled in from the country ISO2 code when omitted, a given name has to match the canonical one ignoring case and is stored in the canonical form.",
                 "consumes": [
                     "application/json"
                 ],
@@ -167,100 +288,1306 @@ const docTemplate = `{
                 "tags": [
                     "banks"
                 ],
-                "summary": "Deletes a bank by SWIFT code",
+                "summary": "Creates a bank",
+                "parameters": [
+                    {
+                        "description": "Bank payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.BankPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/country/{countryISO2code}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets banks with given Country ISO2 Code, ordered by SWIFT code. All banks are returned, unless limit or cursor asks for a page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Gets all banks with given Country ISO2 Code",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "SWIFT Code",
-                        "name": "swift-code",
+                        "description": "Country ISO2 Code",
+                        "name": "countryISO2code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Maximum number of banks in the page, 100 when only cursor is given",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor returned with the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answer with the state at this time, RFC 3339 timestamp or YYYY-MM-DD date",
+                        "name": "asOf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previously received response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a previously received response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/responses.Message"
+                            "$ref": "#/definitions/responses.AllBanks"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Changes whenever a bank in the page changes"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Latest change of a bank in the country, removals included, not sent with asOf"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/responses.Error"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "requests.BankPayload": {
-            "type": "object",
-            "required": [
-                "bankName",
-                "countryISO2",
-                "countryName",
-                "isHeadquarter",
-                "swiftCode"
-            ],
-            "properties": {
-                "address": {
-                    "type": "string",
-                    "maxLength": 255
-                },
-                "bankName": {
-                    "type": "string",
-                    "maxLength": 255
-                },
-                "countryISO2": {
-                    "type": "string"
-                },
-                "countryName": {
-                    "type": "string",
-                    "maxLength": 255
-                },
-                "isHeadquarter": {
-                    "type": "boolean"
-                },
-                "swiftCode": {
-                    "type": "string"
-                }
-            }
         },
-        "responses.AllBanks": {
-            "type": "object",
-            "properties": {
-                "countryISO2": {
-                    "type": "string"
-                },
-                "countryName": {
-                    "type": "string"
-                },
-                "swiftCodes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/responses.BankShort"
+        "/swift-codes/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
-                }
-            }
-        },
-        "responses.BankShort": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "countryISO2": {
-                    "type": "string"
-                },
-                "countryName": {
+                ],
+                "description": "Streams the whole directory, or its part matching the filters. TSV has the same columns as the seed file, so it can be imported back.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Exports banks",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "tsv",
+                            "json",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country ISO2 Code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Export only headquarters or only branches",
+                        "name": "isHeadquarter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/responses.ExportedBank"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Imports banks from a TSV or CSV file with the same columns as the seed file. The file can be sent as multipart form field \"file\" or as a raw request body.",
+                "consumes": [
+                    "multipart/form-data",
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Imports banks from a SWIFT directory file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "SWIFT directory file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "tsv",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "File format, detected from the file name when omitted",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report what would be imported",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/integrity": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reports orphan branches not linked to their existing headquarter, wrong headquarter links and isHeadquarter values disagreeing with the XXX branch code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Checks bank hierarchy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.IntegrityReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/integrity/repair": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fixes issues reported by the integrity check, isHeadquarter follows the XXX branch code and branches are linked to their existing headquarter. Returns the repaired issues.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Repairs bank hierarchy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.IntegrityReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/lookup": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Looks up up to 1000 SWIFT codes in one request. Codes are normalized like in GET /swift-codes/{swift-code}, BIC8 resolves to its XXX headquarter form. Resolved codes are keyed by the requested form and found banks by the resolved code, resolved codes without a bank are listed as unknown and malformed ones as invalid, in the order of the request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Looks up many banks by SWIFT code",
+                "parameters": [
+                    {
+                        "description": "SWIFT codes",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.LookupPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Lookup"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Searches banks by name, town and address. Matching ignores case and accents and tolerates typos, best matches come first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Searches banks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Searched text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country ISO2 Code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Search only headquarters",
+                        "name": "headquartersOnly",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of results",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.SearchResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/{swift-code}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets a bank by SWIFT code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Gets a bank by SWIFT code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SWIFT Code",
+                        "name": "swift-code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answer with the state at this time, RFC 3339 timestamp or YYYY-MM-DD date",
+                        "name": "asOf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previously received response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a previously received response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns either a BankHeadquarter or BankBranch. See the API documentation for details.",
+                        "schema": {},
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Changes whenever the bank or one of its branches changes"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Latest change of the bank or one of its branches, not sent with asOf"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces all fields of a bank. Changing the SWIFT code re-links branches and the headquarter of the bank.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Replaces a bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SWIFT Code",
+                        "name": "swift-code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bank payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.BankPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a bank by SWIFT code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Deletes a bank by SWIFT code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SWIFT Code",
+                        "name": "swift-code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates only the fields present in the payload, the result is validated like a full payload. Changing the SWIFT code re-links branches and the headquarter of the bank.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Updates a bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SWIFT Code",
+                        "name": "swift-code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bank patch payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.BankPatchPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/{swift-code}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets every version of a bank stored under the SWIFT code, oldest first. Deleted banks end with a DELETE version holding their last state.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Gets history of a bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SWIFT Code",
+                        "name": "swift-code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.BankHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/swift-codes/{swift-code}/parse": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Splits a SWIFT code into its ISO 9362 parts without looking it up in the database. Test and training codes have 0, passive participants 1 as the second location character.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "banks"
+                ],
+                "summary": "Parses a SWIFT code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SWIFT Code",
+                        "name": "swift-code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ParsedSWIFTCode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "requests.BankPatchPayload": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bankName": {
+                    "type": "string"
+                },
+                "codeType": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "swiftCode": {
+                    "type": "string"
+                },
+                "timeZone": {
+                    "type": "string"
+                },
+                "townName": {
+                    "type": "string"
+                }
+            }
+        },
+        "requests.BankPayload": {
+            "type": "object",
+            "required": [
+                "bankName",
+                "countryISO2",
+                "isHeadquarter",
+                "swiftCode"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "bankName": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "codeType": {
+                    "type": "string",
+                    "enum": [
+                        "BIC8",
+                        "BIC11"
+                    ]
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "swiftCode": {
+                    "type": "string"
+                },
+                "timeZone": {
+                    "type": "string"
+                },
+                "townName": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "requests.LookupPayload": {
+            "type": "object",
+            "required": [
+                "swiftCodes"
+            ],
+            "properties": {
+                "swiftCodes": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "responses.AllBanks": {
+            "type": "object",
+            "properties": {
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "cursor": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "swiftCodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BankShort"
+                    }
+                }
+            }
+        },
+        "responses.BankChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "$ref": "#/definitions/responses.ExportedBank"
+                },
+                "before": {
+                    "$ref": "#/definitions/responses.ExportedBank"
+                },
+                "changedFields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "swiftCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.BankHeadquarter": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bankName": {
+                    "type": "string"
+                },
+                "branches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BankShort"
+                    }
+                },
+                "codeType": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "swiftCode": {
+                    "type": "string"
+                },
+                "timeZone": {
+                    "type": "string"
+                },
+                "townName": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.BankHistory": {
+            "type": "object",
+            "properties": {
+                "swiftCode": {
+                    "type": "string"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BankVersion"
+                    }
+                }
+            }
+        },
+        "responses.BankShort": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "codeType": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "swiftCode": {
+                    "type": "string"
+                },
+                "timeZone": {
+                    "type": "string"
+                },
+                "townName": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.BankVersion": {
+            "type": "object",
+            "properties": {
+                "bank": {
+                    "$ref": "#/definitions/responses.ExportedBank"
+                },
+                "changedBy": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "validFrom": {
+                    "type": "string"
+                },
+                "validTo": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.CacheStats": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "evictions": {
+                    "type": "integer"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "invalidations": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "responses.Countries": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.Country"
+                    }
+                }
+            }
+        },
+        "responses.Country": {
+            "type": "object",
+            "properties": {
+                "bankCount": {
+                    "type": "integer"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "headquarterCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "responses.ExportedBank": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bankName": {
+                    "type": "string"
+                },
+                "codeType": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "headquarterSwiftCode": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "swiftCode": {
+                    "type": "string"
+                },
+                "timeZone": {
+                    "type": "string"
+                },
+                "townName": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.FieldError": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "JSON name of the field",
+                    "type": "string"
+                },
+                "param": {
+                    "description": "parameter of the rule, e.g. 11 for len=11",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "validation rule the field failed, e.g. required, len or type",
+                    "type": "string"
+                }
+            }
+        },
+        "responses.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.ImportRow"
+                    }
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "row the import stopped at, rows missing from the report were not imported",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/responses.ImportRow"
+                        }
+                    ]
+                },
+                "rejected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.ImportRow"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.ImportRow"
+                    }
+                }
+            }
+        },
+        "responses.ImportRow": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "swiftCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.Institution": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.Country"
+                    }
+                },
+                "headquarters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BankHeadquarter"
+                    }
+                },
+                "institutionCode": {
+                    "type": "string"
+                },
+                "unlinkedBranches": {
+                    "description": "branches without a stored headquarter",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BankShort"
+                    }
+                }
+            }
+        },
+        "responses.IntegrityIssue": {
+            "type": "object",
+            "properties": {
+                "expectedHeadquarterSwiftCode": {
+                    "type": "string"
+                },
+                "expectedIsHeadquarter": {
+                    "type": "boolean"
+                },
+                "headquarterSwiftCode": {
                     "type": "string"
                 },
                 "isHeadquarter": {
@@ -268,17 +1595,68 @@ const docTemplate = `{
                 },
                 "swiftCode": {
                     "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.IntegrityReport": {
+            "type": "object",
+            "properties": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.IntegrityIssue"
+                    }
+                },
+                "repaired": {
+                    "type": "boolean"
                 }
             }
         },
-        "responses.Error": {
+        "responses.InvalidSWIFTCode": {
             "type": "object",
             "properties": {
-                "error": {
+                "reason": {
+                    "type": "string"
+                },
+                "swiftCode": {
                     "type": "string"
                 }
             }
         },
+        "responses.Lookup": {
+            "type": "object",
+            "properties": {
+                "found": {
+                    "description": "found banks keyed by resolved SWIFT code",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/responses.ExportedBank"
+                    }
+                },
+                "invalid": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.InvalidSWIFTCode"
+                    }
+                },
+                "resolved": {
+                    "description": "resolved SWIFT codes keyed by the requested form",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "unknown": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "responses.Message": {
             "type": "object",
             "properties": {
@@ -286,6 +1664,163 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "responses.ParsedSWIFTCode": {
+            "type": "object",
+            "properties": {
+                "branchCode": {
+                    "type": "string"
+                },
+                "countryCode": {
+                    "type": "string"
+                },
+                "institutionCode": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "isPassiveParticipant": {
+                    "type": "boolean"
+                },
+                "isTestCode": {
+                    "type": "boolean"
+                },
+                "locationCode": {
+                    "type": "string"
+                },
+                "swiftCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "stable, machine-readable error code",
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "invalid fields of the request body",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.FieldError"
+                    }
+                },
+                "instance": {
+                    "description": "path of the request",
+                    "type": "string"
+                },
+                "report": {
+                    "description": "rows an interrupted import handled before it stopped",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/responses.ImportReport"
+                        }
+                    ]
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.SearchResult": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bankName": {
+                    "type": "string"
+                },
+                "countryISO2": {
+                    "type": "string"
+                },
+                "countryName": {
+                    "type": "string"
+                },
+                "isHeadquarter": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "swiftCode": {
+                    "type": "string"
+                },
+                "townName": {
+                    "type": "string"
+                }
+            }
+        },
+        "responses.SearchResults": {
+            "type": "object",
+            "properties": {
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.SearchResult"
+                    }
+                }
+            }
+        },
+        "responses.SyncReport": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "inserted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.BankChange"
+                    }
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT as \"Bearer \u003ctoken\u003e\", accepted when a signing key is configured",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
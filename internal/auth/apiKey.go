@@ -0,0 +1,64 @@
+// Package auth issues API keys, verifies bearer tokens and checks their scopes.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"slices"
+	"strings"
+	"time"
+)
+
+// API keys look like swk_<id>_<secret>, the id names the key in listings and logs
+const apiKeyPrefix = "swk"
+
+// NewAPIKey generates a random key. The key is returned only here,
+// the model holds its ID and hash to be stored.
+func NewAPIKey(name string, scopes []string, expiresAt *time.Time) (string, model.APIKey, error) {
+	id := make([]byte, 4)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", model.APIKey{}, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", model.APIKey{}, err
+	}
+
+	apiKey := model.APIKey{
+		ID:        hex.EncodeToString(id),
+		Name:      name,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	key := fmt.Sprintf("%s_%s_%s", apiKeyPrefix, apiKey.ID, base64.RawURLEncoding.EncodeToString(secret))
+	apiKey.Hash = HashAPIKey(key)
+
+	return key, apiKey, nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 of the key. Keys are long and random,
+// so a fast hash is enough and lets keys be looked up by their hash.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// ParseScopes reads a comma separated list of scopes, e.g. read,write
+func ParseScopes(text string) ([]string, error) {
+	var scopes []string
+	for _, scope := range strings.Split(text, ",") {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(model.Scopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q, expected one of %s", scope, strings.Join(model.Scopes, ", "))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
@@ -0,0 +1,343 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// JWTOptions configures JWTVerifier, at least one key source has to be set
+type JWTOptions struct {
+	// shared secret of HS256 tokens
+	HMACSecret []byte
+	// PEM file with RSA and P-256 EC public keys or certificates, for RS256 and ES256 tokens
+	PublicKeyFile string
+	// JSON Web Key Set file with RSA, P-256 EC and symmetric (oct) keys
+	JWKSFile string
+
+	// expected iss and aud claims, both are required
+	Issuer   string
+	Audience string
+	// claim with the permissions of the token, a space separated string or an array of strings
+	ScopeClaim string
+	// values of ScopeClaim granting model.ScopeRead and model.ScopeWrite
+	ReadScope  string
+	WriteScope string
+	// tolerated clock skew of exp and nbf
+	Leeway time.Duration
+}
+
+// Token is a verified JWT
+type Token struct {
+	Subject string
+	// scopes granted by the scope claim, tokens never grant model.ScopeAdmin
+	Scopes []string
+}
+
+// JWTVerifier verifies HS256, RS256 and ES256 tokens against locally configured keys
+type JWTVerifier struct {
+	opts   JWTOptions
+	keys   []verificationKey
+	parser *jwt.Parser
+}
+
+type verificationKey struct {
+	// kid of JWKS keys, empty for other keys
+	id string
+	// []byte, *rsa.PublicKey or *ecdsa.PublicKey
+	key any
+}
+
+func NewJWTVerifier(opts JWTOptions) (*JWTVerifier, error) {
+	if opts.Issuer == "" || opts.Audience == "" {
+		return nil, errors.New("JWT issuer and audience have to be set")
+	}
+	if opts.ScopeClaim == "" || opts.ReadScope == "" || opts.WriteScope == "" {
+		return nil, errors.New("JWT scope claim and its read and write values have to be set")
+	}
+
+	var keys []verificationKey
+	if len(opts.HMACSecret) > 0 {
+		// RFC 7518 asks for a key at least as long as the hash output
+		if len(opts.HMACSecret) < 32 {
+			return nil, errors.New("HS256 secret has to be at least 32 bytes long")
+		}
+		keys = append(keys, verificationKey{key: opts.HMACSecret})
+	}
+	if opts.PublicKeyFile != "" {
+		pemKeys, err := loadPEMKeys(opts.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load JWT public keys: %w", err)
+		}
+		keys = append(keys, pemKeys...)
+	}
+	if opts.JWKSFile != "" {
+		jwksKeys, err := loadJWKS(opts.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load JWKS: %w", err)
+		}
+		keys = append(keys, jwksKeys...)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no JWT verification keys configured")
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		jwt.WithIssuer(opts.Issuer),
+		jwt.WithAudience(opts.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+	)
+
+	return &JWTVerifier{opts: opts, keys: keys, parser: parser}, nil
+}
+
+// Verify checks the signature, exp, nbf, iss and aud of the token and reads its subject and scopes
+func (v *JWTVerifier) Verify(tokenString string) (Token, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(tokenString, claims, v.keyFunc); err != nil {
+		return Token{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Token{}, fmt.Errorf("%w: token has no subject", ErrInvalidToken)
+	}
+
+	values := claimValues(claims[v.opts.ScopeClaim])
+
+	var scopes []string
+	if slices.Contains(values, v.opts.ReadScope) {
+		scopes = append(scopes, model.ScopeRead)
+	}
+	if slices.Contains(values, v.opts.WriteScope) {
+		scopes = append(scopes, model.ScopeWrite)
+	}
+
+	return Token{Subject: subject, Scopes: scopes}, nil
+}
+
+// picks the keys of the signing method of the token, narrowed down by its kid when both have one
+func (v *JWTVerifier) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	var keys jwt.VerificationKeySet
+	for _, key := range v.keys {
+		if kid != "" && key.id != "" && kid != key.id {
+			continue
+		}
+		if keyMatchesMethod(key.key, token.Method) {
+			keys.Keys = append(keys.Keys, key.key)
+		}
+	}
+
+	if len(keys.Keys) == 0 {
+		return nil, fmt.Errorf("no %s key for the token", token.Method.Alg())
+	}
+
+	return keys, nil
+}
+
+func keyMatchesMethod(key any, method jwt.SigningMethod) bool {
+	switch key := key.(type) {
+	case []byte:
+		return method == jwt.SigningMethodHS256
+	case *rsa.PublicKey:
+		return method == jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		return method == jwt.SigningMethodES256 && key.Curve == elliptic.P256()
+	default:
+		return false
+	}
+}
+
+// reads a space separated string or an array of strings
+func claimValues(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return strings.Fields(claim)
+	case []any:
+		var values []string
+		for _, value := range claim {
+			if text, ok := value.(string); ok {
+				values = append(values, text)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func loadPEMKeys(path string) ([]verificationKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []verificationKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var key any
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var certificate *x509.Certificate
+			certificate, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				key = certificate.PublicKey
+			}
+		default:
+			return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			keys = append(keys, verificationKey{key: key})
+		default:
+			return nil, fmt.Errorf("unsupported public key %T", key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded keys found")
+	}
+
+	return keys, nil
+}
+
+// jsonWebKey holds the RFC 7517 members of RSA, EC and oct keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+func loadJWKS(path string) ([]verificationKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, err
+	}
+
+	var keys []verificationKey
+	for _, jwk := range jwks.Keys {
+		// encryption keys are not used to sign tokens
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		keys = append(keys, verificationKey{id: jwk.Kid, key: key})
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys found")
+	}
+
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q, expected P-256", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		curve := elliptic.P256()
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the P-256 curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+		if err != nil {
+			return nil, err
+		}
+		if len(secret) == 0 {
+			return nil, errors.New("empty symmetric key")
+		}
+
+		return secret, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("missing key parameter")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
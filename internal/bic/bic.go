@@ -0,0 +1,145 @@
+// Package bic parses and validates Business Identifier Codes (SWIFT codes) as defined in ISO 9362.
+package bic
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"strings"
+)
+
+// branch code of the primary office (headquarter) of an institution
+const HeadquarterBranchCode = "XXX"
+
+var (
+	ErrInvalidLength          = errors.New("SWIFT code has to be 11 characters long")
+	ErrInvalidInstitutionCode = errors.New("institution code has to be 4 letters or digits")
+	ErrInvalidCountryCode     = errors.New("country code has to be an ISO 3166-1 alpha-2 code")
+	ErrInvalidLocationCode    = errors.New("location code has to be 2 letters or digits, not starting with 0 or 1 and not ending with letter O")
+	ErrInvalidBranchCode      = errors.New("branch code has to be 3 letters or digits, starting with X only for XXX")
+	ErrCountryMismatch        = errors.New("country code of SWIFT code does not match country ISO2 code")
+)
+
+var validate = validator.New()
+
+type BIC struct {
+	InstitutionCode string
+	CountryCode     string
+	LocationCode    string
+	BranchCode      string
+}
+
+// Normalize removes white space from the code and upper-cases it. An 8 character code (BIC8)
+// identifies the primary office of the institution, so it gets the XXX branch code.
+func Normalize(code string) string {
+	code = strings.ToUpper(strings.Join(strings.Fields(code), ""))
+	if len(code) == 8 {
+		code += HeadquarterBranchCode
+	}
+
+	return code
+}
+
+// Parse splits an 11 character SWIFT code into its parts and validates their character classes.
+// The code has to be upper case.
+func Parse(code string) (BIC, error) {
+	if len(code) != 11 {
+		return BIC{}, ErrInvalidLength
+	}
+
+	bic := BIC{
+		InstitutionCode: code[0:4],
+		CountryCode:     code[4:6],
+		LocationCode:    code[6:8],
+		BranchCode:      code[8:11],
+	}
+
+	// ISO 9362:2014 allows digits in the institution (business party prefix) code
+	if !isAlphanumeric(bic.InstitutionCode) {
+		return BIC{}, ErrInvalidInstitutionCode
+	}
+
+	if !isLetters(bic.CountryCode) || validate.Var(bic.CountryCode, "iso3166_1_alpha2") != nil {
+		return BIC{}, ErrInvalidCountryCode
+	}
+
+	// 0 and 1 are reserved for the second character, O is not used to avoid confusion with 0
+	if !isAlphanumeric(bic.LocationCode) || bic.LocationCode[0] == '0' || bic.LocationCode[0] == '1' || bic.LocationCode[1] == 'O' {
+		return BIC{}, ErrInvalidLocationCode
+	}
+
+	if !isAlphanumeric(bic.BranchCode) || (bic.BranchCode[0] == 'X' && bic.BranchCode != HeadquarterBranchCode) {
+		return BIC{}, ErrInvalidBranchCode
+	}
+
+	return bic, nil
+}
+
+// ParseForCountry parses the SWIFT code and checks that its country code is countryISO2
+func ParseForCountry(code, countryISO2 string) (BIC, error) {
+	bic, err := Parse(code)
+	if err != nil {
+		return BIC{}, err
+	}
+
+	if bic.CountryCode != countryISO2 {
+		return BIC{}, fmt.Errorf("%w: %s is not %s", ErrCountryMismatch, bic.CountryCode, countryISO2)
+	}
+
+	return bic, nil
+}
+
+// ValidateInstitutionCode checks the 4 character institution code shared by SWIFT codes of a banking group.
+// The code has to be upper case.
+func ValidateInstitutionCode(code string) error {
+	if len(code) != 4 || !isAlphanumeric(code) {
+		return ErrInvalidInstitutionCode
+	}
+
+	return nil
+}
+
+func (b BIC) String() string {
+	return b.InstitutionCode + b.CountryCode + b.LocationCode + b.BranchCode
+}
+
+// IsHeadquarter reports whether the code identifies the primary office of the institution
+func (b BIC) IsHeadquarter() bool {
+	return b.BranchCode == HeadquarterBranchCode
+}
+
+// HeadquarterCode returns SWIFT code of the primary office of the institution
+func (b BIC) HeadquarterCode() string {
+	return b.InstitutionCode + b.CountryCode + b.LocationCode + HeadquarterBranchCode
+}
+
+// IsTest reports whether the code is a test and training code, with 0 as the second location character
+func (b BIC) IsTest() bool {
+	return b.LocationCode[1] == '0'
+}
+
+// IsPassiveParticipant reports whether the code belongs to a participant not connected
+// to the SWIFT network, with 1 as the second location character
+func (b BIC) IsPassiveParticipant() bool {
+	return b.LocationCode[1] == '1'
+}
+
+func isLetters(value string) bool {
+	for _, c := range value {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAlphanumeric(value string) bool {
+	for _, c := range value {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
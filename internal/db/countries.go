@@ -0,0 +1,31 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"strings"
+)
+
+// ErrInvalidCountry is returned for banks whose country name does not match the country ISO2 code
+var ErrInvalidCountry = errors.New("invalid country")
+
+// CanonicalizeCountryName fills in a missing country name of the bank and replaces
+// a given one with the canonical name, names differing in more than case are rejected
+func CanonicalizeCountryName(bank *model.Bank) error {
+	name, ok := store.CountryName(bank.CountryISO2)
+	if !ok {
+		return fmt.Errorf("%w: unknown country ISO2 code %s", ErrInvalidCountry, bank.CountryISO2)
+	}
+
+	countryName := strings.TrimSpace(bank.CountryName)
+	if countryName != "" && !strings.EqualFold(countryName, name) {
+		return fmt.Errorf("%w: country name %q does not match country ISO2 code %s, expected %q",
+			ErrInvalidCountry, bank.CountryName, bank.CountryISO2, name)
+	}
+
+	bank.CountryName = name
+
+	return nil
+}
@@ -3,8 +3,11 @@ package db
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
 	"time"
+
+	_ "modernc.org/sqlite"
 )
 
 func New(addr string, maxOpenConns, maxIdleCons int, maxIdleTime string) (*sql.DB, error) {
@@ -32,8 +35,29 @@ func New(addr string, maxOpenConns, maxIdleCons int, maxIdleTime string) (*sql.D
 	return db, nil
 }
 
-func SeedDBIfEmpty(db *sql.DB, store store.Storage) error {
-	ctx := context.Background()
+// NewSQLite opens the SQLite database file, creating it if needed
+func NewSQLite(path string) (*sql.DB, error) {
+	// foreign keys are off by default, writers wait for each other instead of failing with SQLITE_BUSY
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate", path)
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// SeedDBIfEmpty seeds the storage only if the banks table is empty,
+// the returned result is nil when seeding was skipped
+func SeedDBIfEmpty(ctx context.Context, db *sql.DB, storage store.Storage, opts SeedOptions) (*store.LoadResult, error) {
 	var count int
 
 	query := `
@@ -42,12 +66,17 @@ func SeedDBIfEmpty(db *sql.DB, store store.Storage) error {
 	`
 	err := db.QueryRowContext(ctx, query).Scan(&count)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, nil
 	}
 
-	if count == 0 {
-		return Seed(store)
+	result, err := Seed(ctx, storage, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &result, nil
 }
@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"io"
+	"sort"
+)
+
+type ImportOptions struct {
+	// field delimiter of the file, '\t' for TSV and ',' for CSV
+	Comma  rune
+	DryRun bool
+}
+
+type ImportRow struct {
+	Line      int
+	SWIFTCode string
+	Reason    string
+}
+
+type ImportReport struct {
+	DryRun   bool
+	Created  []ImportRow
+	Skipped  []ImportRow
+	Rejected []ImportRow
+	// row the import stopped at, when the storage failed to check or create its bank.
+	// Rows missing from the report were not imported.
+	Failed *ImportRow
+}
+
+type importedBank struct {
+	line int
+	bank model.Bank
+}
+
+// number of rows checked against the storage together
+const importBatchSize = 500
+
+// Import loads a SWIFT directory file with the same layout as the seed file.
+// Unlike Seed it does not stop at the first bad row, every row ends up either
+// created, skipped (already exists) or rejected in the returned report.
+// Rows are read and imported in batches, headquarters of a batch are created
+// before its branches. Branches are linked to their headquarters wherever they
+// are in the file, a headquarter adopts branches created before it.
+// When the import stops on an error, the report of rows handled so far is
+// returned with it.
+func Import(ctx context.Context, storage store.Storage, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Comma
+	reader.FieldsPerRecord = -1
+
+	report := &ImportReport{DryRun: opts.DryRun}
+
+	seenLines := make(map[string]int)
+	batch := make([]importedBank, 0, importBatchSize)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return sortImportReport(report), err
+			}
+			report.Rejected = append(report.Rejected, ImportRow{Line: parseErr.StartLine, Reason: parseErr.Err.Error()})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		if isHeaderRecord(record) {
+			continue
+		}
+
+		parsedRecord, err := parseRecord(record)
+		if err != nil {
+			report.Rejected = append(report.Rejected, ImportRow{Line: line, Reason: err.Error()})
+			continue
+		}
+
+		bank, err := parsedRecord.toBank()
+		if err != nil {
+			report.Rejected = append(report.Rejected, ImportRow{Line: line, SWIFTCode: parsedRecord.SWIFTCode, Reason: err.Error()})
+			continue
+		}
+
+		// banks get the same canonical country names as the ones created one by one
+		if err := CanonicalizeCountryName(&bank); err != nil {
+			report.Rejected = append(report.Rejected, ImportRow{Line: line, SWIFTCode: bank.SWIFTCode, Reason: err.Error()})
+			continue
+		}
+
+		if firstLine, ok := seenLines[bank.SWIFTCode]; ok {
+			report.Skipped = append(report.Skipped, ImportRow{
+				Line:      line,
+				SWIFTCode: bank.SWIFTCode,
+				Reason:    fmt.Sprintf("duplicate of line %d", firstLine),
+			})
+			continue
+		}
+		seenLines[bank.SWIFTCode] = line
+
+		batch = append(batch, importedBank{line: line, bank: bank})
+		if len(batch) == importBatchSize {
+			if err := importBatch(ctx, storage, batch, report); err != nil {
+				return sortImportReport(report), err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if err := importBatch(ctx, storage, batch, report); err != nil {
+		return sortImportReport(report), err
+	}
+
+	return sortImportReport(report), nil
+}
+
+// creates banks of the batch missing from the storage, or only reports them in a dry run
+func importBatch(ctx context.Context, storage store.Storage, batch []importedBank, report *ImportReport) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	swiftCodes := make([]string, 0, len(batch))
+	for _, imported := range batch {
+		swiftCodes = append(swiftCodes, imported.bank.SWIFTCode)
+	}
+
+	existingBanks, err := storage.Banks.GetAllBySWIFTCodes(ctx, swiftCodes)
+	if err != nil {
+		report.Failed = &ImportRow{Line: batch[0].line, SWIFTCode: batch[0].bank.SWIFTCode, Reason: "banks could not be checked"}
+		return fmt.Errorf("line %d: %w", batch[0].line, err)
+	}
+
+	existing := make(map[string]bool, len(existingBanks))
+	for _, bank := range existingBanks {
+		existing[bank.SWIFTCode] = true
+	}
+
+	// headquarters first, so branches of the batch are created linked to them
+	sort.SliceStable(batch, func(i, j int) bool {
+		return batch[i].bank.IsHeadquarter && !batch[j].bank.IsHeadquarter
+	})
+
+	for _, imported := range batch {
+		row := ImportRow{Line: imported.line, SWIFTCode: imported.bank.SWIFTCode}
+
+		if existing[imported.bank.SWIFTCode] {
+			row.Reason = "bank already exists"
+			report.Skipped = append(report.Skipped, row)
+			continue
+		}
+
+		if report.DryRun {
+			report.Created = append(report.Created, row)
+			continue
+		}
+
+		err := storage.Banks.Create(ctx, &imported.bank)
+		switch {
+		case err == nil:
+			report.Created = append(report.Created, row)
+		case errors.Is(err, store.ErrAlreadyExists):
+			// created concurrently after the check
+			row.Reason = "bank already exists"
+			report.Skipped = append(report.Skipped, row)
+		case errors.Is(err, store.ErrConflict):
+			row.Reason = "bank conflicts with a concurrent change"
+			report.Rejected = append(report.Rejected, row)
+		default:
+			row.Reason = "bank could not be stored"
+			report.Failed = &row
+			return fmt.Errorf("line %d: %w", imported.line, err)
+		}
+	}
+
+	return nil
+}
+
+func sortImportReport(report *ImportReport) *ImportReport {
+	sortImportRows(report.Created)
+	sortImportRows(report.Skipped)
+	sortImportRows(report.Rejected)
+
+	return report
+}
+
+func sortImportRows(rows []ImportRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Line < rows[j].Line
+	})
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/bic"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"strings"
+	"time"
+)
+
+// number of columns in a SWIFT directory file, see internal/db/seed/SWIFT_CODES.tsv
+const recordFieldsCount = 8
+
+// header row of a SWIFT directory file
+var recordHeader = []string{
+	"COUNTRY ISO2 CODE",
+	"SWIFT CODE",
+	"CODE TYPE",
+	"NAME",
+	"ADDRESS",
+	"TOWN NAME",
+	"COUNTRY NAME",
+	"TIME ZONE",
+}
+
+type BankRecord struct {
+	CountryISO2 string
+	SWIFTCode   string
+	CodeType    string
+	Name        string
+	Address     *string
+	TownName    string
+	CountryName string
+	TimeZone    string
+}
+
+func parseRecord(record []string) (BankRecord, error) {
+	if len(record) != recordFieldsCount {
+		return BankRecord{}, fmt.Errorf("expected %d fields, got %d", recordFieldsCount, len(record))
+	}
+
+	return BankRecord{
+		CountryISO2: record[0],
+		SWIFTCode:   record[1],
+		CodeType:    record[2],
+		Name:        record[3],
+		Address:     optionalString(record[4]),
+		TownName:    record[5],
+		CountryName: record[6],
+		TimeZone:    record[7],
+	}, nil
+}
+
+// RecordHeader returns the header row of a SWIFT directory file
+func RecordHeader() []string {
+	return append([]string(nil), recordHeader...)
+}
+
+// FormatRecord maps the bank to a row of a SWIFT directory file,
+// it is the inverse of parseRecord
+func FormatRecord(bank model.Bank) []string {
+	return []string{
+		bank.CountryISO2,
+		bank.SWIFTCode,
+		valueOrEmpty(bank.CodeType),
+		bank.BankName,
+		valueOrEmpty(bank.Address),
+		valueOrEmpty(bank.TownName),
+		bank.CountryName,
+		valueOrEmpty(bank.TimeZone),
+	}
+}
+
+// checks if the record is a header row of a SWIFT directory file
+func isHeaderRecord(record []string) bool {
+	return len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), recordHeader[0])
+}
+
+// validates the record and maps it to a bank, rejecting time zones
+// that are not in the IANA tz database
+func (r BankRecord) toBank() (model.Bank, error) {
+	// codes are normalized like in the API, BIC8 becomes its XXX headquarter form
+	swiftCode := bic.Normalize(r.SWIFTCode)
+	countryISO2 := strings.ToUpper(strings.TrimSpace(r.CountryISO2))
+
+	if len(countryISO2) != 2 {
+		return model.Bank{}, fmt.Errorf("invalid country ISO2 code %q", r.CountryISO2)
+	}
+
+	code, err := bic.ParseForCountry(swiftCode, countryISO2)
+	if err != nil {
+		return model.Bank{}, fmt.Errorf("invalid SWIFT code %q: %w", r.SWIFTCode, err)
+	}
+
+	if optionalString(r.Name) == nil {
+		return model.Bank{}, errors.New("missing bank name")
+	}
+
+	if optionalString(r.CountryName) == nil {
+		return model.Bank{}, errors.New("missing country name")
+	}
+
+	timeZone := optionalString(r.TimeZone)
+	if timeZone != nil {
+		if _, err := time.LoadLocation(*timeZone); err != nil || *timeZone == "Local" {
+			return model.Bank{}, fmt.Errorf("invalid time zone %q", *timeZone)
+		}
+	}
+
+	return model.Bank{
+		SWIFTCode:     swiftCode,
+		Address:       r.Address,
+		BankName:      r.Name,
+		CountryISO2:   countryISO2,
+		CountryName:   r.CountryName,
+		IsHeadquarter: code.IsHeadquarter(),
+		TownName:      optionalString(r.TownName),
+		TimeZone:      timeZone,
+		CodeType:      optionalString(r.CodeType),
+	}, nil
+}
+
+func optionalString(value string) *string {
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil
+	}
+
+	return &value
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
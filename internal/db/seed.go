@@ -3,121 +3,96 @@ package db
 import (
 	"context"
 	"encoding/csv"
+	"errors"
+	"fmt"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"io"
 	"os"
-	"path/filepath"
-	"strings"
 )
 
-type BankRecord struct {
-	CountryISO2 string
-	SWIFTCode   string
-	CodeType    string
-	Name        string
-	Address     *string
-	TownName    string
-	CountryName string
-	TimeZone    string
-}
-
-func Seed(store store.Storage) error {
-	ctx := context.Background()
+// DefaultSeedPath is the SWIFT directory file shipped with the app
+const DefaultSeedPath = "internal/db/seed/SWIFT_CODES.tsv"
 
-	seedFilePath, err := filepath.Abs("internal/db/seed/SWIFT_CODES.tsv")
-	if err != nil {
-		return err
-	}
+// ErrInvalidFile wraps errors of rows that cannot be read or parsed
+var ErrInvalidFile = errors.New("invalid SWIFT directory file")
 
-	f, err := os.Open(seedFilePath)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
+// number of rows read between two progress reports
+const seedProgressInterval = 10000
 
-	reader := csv.NewReader(f)
-	reader.Comma = '\t'
+type SeedOptions struct {
+	// SWIFT directory file in TSV format
+	Path string
+	// called with the number of rows read so far, every seedProgressInterval rows
+	// and after the last row, can be nil
+	Progress func(rows int)
+}
 
-	records, err := reader.ReadAll()
+// Seed streams the SWIFT directory file to the storage, which loads it in a single
+// transaction, so a bad row leaves the storage unchanged. Seeding is idempotent,
+// banks already stored are updated to match the file.
+func Seed(ctx context.Context, storage store.Storage, opts SeedOptions) (store.LoadResult, error) {
+	f, err := os.Open(opts.Path)
 	if err != nil {
-		return err
+		return store.LoadResult{}, err
 	}
+	defer f.Close()
 
-	// skip the header row
-	records = records[1:]
-
-	headquartersMap := make(map[string]string)
-
-	// first pass, fill headquarters
-	for _, record := range records {
-		parsedRecord := parseRecord(record)
-
-		if parsedRecord.SWIFTCode[8:] == "XXX" {
-
-			var bank model.Bank
-
-			bank.IsHeadquarter = true
-			bank.SWIFTCode = parsedRecord.SWIFTCode
-			bank.CountryISO2 = parsedRecord.CountryISO2
-			bank.BankName = parsedRecord.Name
-			bank.Address = parsedRecord.Address
-			bank.CountryName = parsedRecord.CountryName
-			bank.HeadquarterSWIFTCode = nil
-
-			headquartersMap[parsedRecord.SWIFTCode[:8]] = parsedRecord.SWIFTCode
+	return storage.Banks.Load(ctx, readBanks(f, '\t', opts.Progress), store.LoadOptions{})
+}
 
-			if err := store.Banks.Create(ctx, &bank); err != nil {
-				return err
+// returns a source of banks read from a SWIFT directory file, it stops at the first invalid row.
+// Country names are canonicalized like the ones of banks created one by one.
+func readBanks(r io.Reader, comma rune, progress func(rows int)) store.BankSource {
+	return func(fn func(model.Bank) error) error {
+		reader := csv.NewReader(r)
+		reader.Comma = comma
+		reader.FieldsPerRecord = -1
+		reader.ReuseRecord = true
+
+		rows := 0
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidFile, err)
 			}
-		}
-	}
 
-	// second pass, fill branches
-	for _, record := range records {
-		parsedRecord := parseRecord(record)
+			line, _ := reader.FieldPos(0)
+			if line == 1 && isHeaderRecord(record) {
+				continue
+			}
 
-		if parsedRecord.SWIFTCode[8:] != "XXX" {
+			parsedRecord, err := parseRecord(record)
+			if err != nil {
+				return fmt.Errorf("%w: line %d: %w", ErrInvalidFile, line, err)
+			}
 
-			var bank model.Bank
+			bank, err := parsedRecord.toBank()
+			if err != nil {
+				return fmt.Errorf("%w: line %d: %w", ErrInvalidFile, line, err)
+			}
 
-			bank.IsHeadquarter = false
-			bank.SWIFTCode = parsedRecord.SWIFTCode
-			bank.CountryISO2 = parsedRecord.CountryISO2
-			bank.BankName = parsedRecord.Name
-			bank.Address = parsedRecord.Address
-			bank.CountryName = parsedRecord.CountryName
+			if err := CanonicalizeCountryName(&bank); err != nil {
+				return fmt.Errorf("%w: line %d: %w", ErrInvalidFile, line, err)
+			}
 
-			if hqCode, ok := headquartersMap[parsedRecord.SWIFTCode[:8]]; ok {
-				bank.HeadquarterSWIFTCode = &hqCode
-			} else {
-				bank.HeadquarterSWIFTCode = nil
+			if err := fn(bank); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
 			}
 
-			if err := store.Banks.Create(ctx, &bank); err != nil {
-				return err
+			rows++
+			if progress != nil && rows%seedProgressInterval == 0 {
+				progress(rows)
 			}
 		}
-	}
 
-	return nil
-}
-
-func parseRecord(record []string) BankRecord {
-	var address *string
-	if record[4] == "" || len(strings.TrimSpace(record[4])) == 0 {
-		address = nil
-	} else {
-		address = &record[4]
-	}
+		if progress != nil && rows%seedProgressInterval != 0 {
+			progress(rows)
+		}
 
-	return BankRecord{
-		CountryISO2: record[0],
-		SWIFTCode:   record[1],
-		CodeType:    record[2],
-		Name:        record[3],
-		Address:     address,
-		TownName:    record[5],
-		CountryName: record[6],
-		TimeZone:    record[7],
+		return nil
 	}
 }
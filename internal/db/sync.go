@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"io"
+)
+
+// ErrEmptyRelease is returned for a release without banks, which would remove all of them
+var ErrEmptyRelease = errors.New("release has no banks")
+
+type SyncOptions struct {
+	// field delimiter of the file, '\t' for TSV and ',' for CSV
+	Comma  rune
+	DryRun bool
+	// applies a release without banks instead of rejecting it with ErrEmptyRelease
+	AllowEmpty bool
+	// called with the number of rows read so far, can be nil
+	Progress func(rows int)
+}
+
+type SyncReport struct {
+	DryRun bool
+	store.LoadResult
+}
+
+// Sync makes the storage match a new release of the SWIFT directory: new banks are inserted,
+// changed ones updated, banks missing from the release removed and branches re-linked to
+// their headquarters. Unlike Import it stops at the first invalid row, the release is
+// applied in a single transaction or not at all.
+func Sync(ctx context.Context, storage store.Storage, r io.Reader, opts SyncOptions) (*SyncReport, error) {
+	source := readBanks(r, opts.Comma, opts.Progress)
+	if !opts.AllowEmpty {
+		source = rejectEmpty(source)
+	}
+
+	result, err := storage.Banks.Load(ctx, source, store.LoadOptions{
+		Remove: true,
+		DryRun: opts.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncReport{DryRun: opts.DryRun, LoadResult: result}, nil
+}
+
+// rejectEmpty fails the source when it has no banks, before Load removes the stored ones
+func rejectEmpty(source store.BankSource) store.BankSource {
+	return func(fn func(model.Bank) error) error {
+		banks := 0
+		err := source(func(bank model.Bank) error {
+			banks++
+			return fn(bank)
+		})
+		if err == nil && banks == 0 {
+			return ErrEmptyRelease
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,37 @@
+package requests
+
+// BankPatchPayload holds fields to change, omitted (or null) fields are left untouched.
+// Optional text fields are cleared with an empty string.
+type BankPatchPayload struct {
+	SWIFTCode     *string `json:"swiftCode"`
+	Address       *string `json:"address"`
+	BankName      *string `json:"bankName"`
+	CountryISO2   *string `json:"countryISO2"`
+	CountryName   *string `json:"countryName"`
+	IsHeadquarter *bool   `json:"isHeadquarter"`
+	TownName      *string `json:"townName"`
+	TimeZone      *string `json:"timeZone"`
+	CodeType      *string `json:"codeType"`
+}
+
+// Apply overwrites fields of the payload with the fields present in the patch
+func (p BankPatchPayload) Apply(payload *BankPayload) {
+	applyString(&payload.SWIFTCode, p.SWIFTCode)
+	applyString(&payload.Address, p.Address)
+	applyString(&payload.BankName, p.BankName)
+	applyString(&payload.CountryISO2, p.CountryISO2)
+	applyString(&payload.CountryName, p.CountryName)
+	applyString(&payload.TownName, p.TownName)
+	applyString(&payload.TimeZone, p.TimeZone)
+	applyString(&payload.CodeType, p.CodeType)
+
+	if p.IsHeadquarter != nil {
+		payload.IsHeadquarter = p.IsHeadquarter
+	}
+}
+
+func applyString(field *string, value *string) {
+	if value != nil {
+		*field = *value
+	}
+}
@@ -5,6 +5,9 @@ type BankPayload struct {
 	Address       string `json:"address" validate:"max=255"`
 	BankName      string `json:"bankName" validate:"required,max=255"`
 	CountryISO2   string `json:"countryISO2" validate:"required,len=2,iso3166_1_alpha2"`
-	CountryName   string `json:"countryName" validate:"required,max=255"`
+	CountryName   string `json:"countryName" validate:"max=255"`
 	IsHeadquarter *bool  `json:"isHeadquarter" validate:"required,boolean"`
+	TownName      string `json:"townName" validate:"max=255"`
+	TimeZone      string `json:"timeZone" validate:"omitempty,timezone"`
+	CodeType      string `json:"codeType" validate:"omitempty,oneof=BIC8 BIC11"`
 }
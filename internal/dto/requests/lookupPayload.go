@@ -0,0 +1,5 @@
+package requests
+
+type LookupPayload struct {
+	SWIFTCodes []string `json:"swiftCodes" validate:"required,min=1,max=1000"`
+}
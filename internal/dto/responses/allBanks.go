@@ -4,4 +4,7 @@ type AllBanks struct {
 	CountryISO2 string      `json:"countryISO2"`
 	CountryName string      `json:"countryName"`
 	SwiftCodes  []BankShort `json:"swiftCodes"`
+	Limit       int         `json:"limit,omitempty"`
+	Cursor      *string     `json:"cursor"`
+	NextCursor  *string     `json:"nextCursor"`
 }
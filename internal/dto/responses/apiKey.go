@@ -0,0 +1,17 @@
+package responses
+
+import "time"
+
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// CreatedAPIKey is the only place the key itself is shown
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -7,4 +7,7 @@ type BankBranch struct {
 	CountryISO2   string  `json:"countryISO2"`
 	CountryName   string  `json:"countryName"`
 	IsHeadquarter bool    `json:"isHeadquarter"`
+	TownName      *string `json:"townName"`
+	TimeZone      *string `json:"timeZone"`
+	CodeType      *string `json:"codeType"`
 }
@@ -7,6 +7,9 @@ type BankHeadquarter struct {
 	CountryISO2   string      `json:"countryISO2"`
 	CountryName   string      `json:"countryName"`
 	IsHeadquarter bool        `json:"isHeadquarter"`
+	TownName      *string     `json:"townName"`
+	TimeZone      *string     `json:"timeZone"`
+	CodeType      *string     `json:"codeType"`
 	Branches      []BankShort `json:"branches"`
 }
 type BankShort struct {
@@ -15,4 +18,7 @@ type BankShort struct {
 	CountryISO2   string  `json:"countryISO2"`
 	CountryName   string  `json:"countryName"`
 	IsHeadquarter bool    `json:"isHeadquarter"`
+	TownName      *string `json:"townName"`
+	TimeZone      *string `json:"timeZone"`
+	CodeType      *string `json:"codeType"`
 }
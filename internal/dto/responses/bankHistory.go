@@ -0,0 +1,16 @@
+package responses
+
+import "time"
+
+type BankHistory struct {
+	SWIFTCode string        `json:"swiftCode"`
+	Versions  []BankVersion `json:"versions"`
+}
+
+type BankVersion struct {
+	Operation string       `json:"operation"`
+	ValidFrom time.Time    `json:"validFrom"`
+	ValidTo   *time.Time   `json:"validTo"`
+	ChangedBy *string      `json:"changedBy"`
+	Bank      ExportedBank `json:"bank"`
+}
@@ -0,0 +1,11 @@
+package responses
+
+type CacheStats struct {
+	Enabled       bool   `json:"enabled"`
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+	Size          int    `json:"size"`
+}
@@ -0,0 +1,12 @@
+package responses
+
+type Countries struct {
+	Countries []Country `json:"countries"`
+}
+
+type Country struct {
+	CountryISO2      string `json:"countryISO2"`
+	CountryName      string `json:"countryName"`
+	BankCount        int    `json:"bankCount"`
+	HeadquarterCount int    `json:"headquarterCount"`
+}
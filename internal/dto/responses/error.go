@@ -1,5 +0,0 @@
-package responses
-
-type Error struct {
-	Error string `json:"error"`
-}
@@ -0,0 +1,14 @@
+package responses
+
+type ExportedBank struct {
+	SWIFTCode            string  `json:"swiftCode"`
+	Address              *string `json:"address"`
+	BankName             string  `json:"bankName"`
+	CountryISO2          string  `json:"countryISO2"`
+	CountryName          string  `json:"countryName"`
+	IsHeadquarter        bool    `json:"isHeadquarter"`
+	HeadquarterSWIFTCode *string `json:"headquarterSwiftCode"`
+	TownName             *string `json:"townName"`
+	TimeZone             *string `json:"timeZone"`
+	CodeType             *string `json:"codeType"`
+}
@@ -0,0 +1,16 @@
+package responses
+
+type ImportReport struct {
+	DryRun   bool        `json:"dryRun"`
+	Created  []ImportRow `json:"created"`
+	Skipped  []ImportRow `json:"skipped"`
+	Rejected []ImportRow `json:"rejected"`
+	// row the import stopped at, rows missing from the report were not imported
+	Failed *ImportRow `json:"failed,omitempty"`
+}
+
+type ImportRow struct {
+	Line      int    `json:"line"`
+	SWIFTCode string `json:"swiftCode,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
@@ -0,0 +1,9 @@
+package responses
+
+type Institution struct {
+	InstitutionCode string            `json:"institutionCode"`
+	Countries       []Country         `json:"countries"`
+	Headquarters    []BankHeadquarter `json:"headquarters"`
+	// branches without a stored headquarter
+	UnlinkedBranches []BankShort `json:"unlinkedBranches"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+type IntegrityReport struct {
+	Repaired bool             `json:"repaired"`
+	Issues   []IntegrityIssue `json:"issues"`
+}
+
+type IntegrityIssue struct {
+	SWIFTCode                    string  `json:"swiftCode"`
+	Type                         string  `json:"type"`
+	IsHeadquarter                bool    `json:"isHeadquarter"`
+	HeadquarterSWIFTCode         *string `json:"headquarterSwiftCode"`
+	ExpectedIsHeadquarter        bool    `json:"expectedIsHeadquarter"`
+	ExpectedHeadquarterSWIFTCode *string `json:"expectedHeadquarterSwiftCode"`
+}
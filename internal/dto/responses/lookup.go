@@ -0,0 +1,15 @@
+package responses
+
+type Lookup struct {
+	// resolved SWIFT codes keyed by the requested form
+	Resolved map[string]string `json:"resolved"`
+	// found banks keyed by resolved SWIFT code
+	Found   map[string]ExportedBank `json:"found"`
+	Unknown []string                `json:"unknown"`
+	Invalid []InvalidSWIFTCode      `json:"invalid"`
+}
+
+type InvalidSWIFTCode struct {
+	SWIFTCode string `json:"swiftCode"`
+	Reason    string `json:"reason"`
+}
@@ -0,0 +1,12 @@
+package responses
+
+type ParsedSWIFTCode struct {
+	SWIFTCode            string `json:"swiftCode"`
+	InstitutionCode      string `json:"institutionCode"`
+	CountryCode          string `json:"countryCode"`
+	LocationCode         string `json:"locationCode"`
+	BranchCode           string `json:"branchCode"`
+	IsHeadquarter        bool   `json:"isHeadquarter"`
+	IsTestCode           bool   `json:"isTestCode"`
+	IsPassiveParticipant bool   `json:"isPassiveParticipant"`
+}
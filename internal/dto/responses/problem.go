@@ -0,0 +1,28 @@
+package responses
+
+// Problem is an RFC 9457 (formerly RFC 7807) problem details body, sent as application/problem+json
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	// path of the request
+	Instance string `json:"instance"`
+	// stable, machine-readable error code
+	Code      string `json:"code"`
+	RequestID string `json:"requestId,omitempty"`
+	// invalid fields of the request body
+	Errors []FieldError `json:"errors,omitempty"`
+	// rows an interrupted import handled before it stopped
+	Report *ImportReport `json:"report,omitempty"`
+}
+
+type FieldError struct {
+	// JSON name of the field
+	Field string `json:"field"`
+	// validation rule the field failed, e.g. required, len or type
+	Rule string `json:"rule"`
+	// parameter of the rule, e.g. 11 for len=11
+	Param  string `json:"param,omitempty"`
+	Detail string `json:"detail"`
+}
@@ -0,0 +1,17 @@
+package responses
+
+type SearchResults struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+type SearchResult struct {
+	SWIFTCode     string  `json:"swiftCode"`
+	BankName      string  `json:"bankName"`
+	Address       *string `json:"address"`
+	TownName      *string `json:"townName"`
+	CountryISO2   string  `json:"countryISO2"`
+	CountryName   string  `json:"countryName"`
+	IsHeadquarter bool    `json:"isHeadquarter"`
+	Score         float64 `json:"score"`
+}
@@ -0,0 +1,16 @@
+package responses
+
+type SyncReport struct {
+	DryRun    bool         `json:"dryRun"`
+	Inserted  []string     `json:"inserted"`
+	Updated   []BankChange `json:"updated"`
+	Removed   []string     `json:"removed"`
+	Unchanged int          `json:"unchanged"`
+}
+
+type BankChange struct {
+	SWIFTCode     string       `json:"swiftCode"`
+	ChangedFields []string     `json:"changedFields"`
+	Before        ExportedBank `json:"before"`
+	After         ExportedBank `json:"after"`
+}
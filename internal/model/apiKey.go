@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// Scopes lists every scope an API key can carry
+var Scopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// APIKey grants its scopes until ExpiresAt, nil ExpiresAt never expires.
+// Only the SHA-256 Hash of the key is stored, the key itself is shown once when created.
+type APIKey struct {
+	ID        string
+	Name      string
+	Hash      string
+	Scopes    []string
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
+
+func (k APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Bank struct {
 	SWIFTCode            string  `json:"swiftCode"`
 	Address              *string `json:"address"`
@@ -8,4 +10,10 @@ type Bank struct {
 	CountryName          string  `json:"countryName"`
 	IsHeadquarter        bool    `json:"isHeadquarter"`
 	HeadquarterSWIFTCode *string `json:"headquarterSwiftCode"`
+	TownName             *string `json:"townName"`
+	TimeZone             *string `json:"timeZone"`
+	CodeType             *string `json:"codeType"`
+	// set by GetBySWIFTCode and GetAllByCountryISO2, zero in history
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+const (
+	OperationInsert = "INSERT"
+	OperationUpdate = "UPDATE"
+	OperationDelete = "DELETE"
+)
+
+// BankVersion is the state of a bank between ValidFrom and ValidTo, current version has nil ValidTo.
+// Version with OperationDelete marks the bank as deleted and holds its last state.
+type BankVersion struct {
+	Bank      Bank
+	Operation string
+	ValidFrom time.Time
+	ValidTo   *time.Time
+	// who made the change, nil when unknown
+	ChangedBy *string
+}
@@ -0,0 +1,9 @@
+package model
+
+// Country is an ISO 3166 country with the number of banks stored for it
+type Country struct {
+	ISO2             string
+	Name             string
+	BankCount        int
+	HeadquarterCount int
+}
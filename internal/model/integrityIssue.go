@@ -0,0 +1,20 @@
+package model
+
+const (
+	// IssueOrphanBranch is a branch not linked to the existing headquarter with the same 8 character prefix
+	IssueOrphanBranch = "ORPHAN_BRANCH"
+	// IssueWrongHeadquarterLink is a bank linked to other bank than the headquarter with the same 8 character prefix
+	IssueWrongHeadquarterLink = "WRONG_HEADQUARTER_LINK"
+	// IssueHeadquarterFlagMismatch is a bank with isHeadquarter disagreeing with the XXX branch code
+	IssueHeadquarterFlagMismatch = "HEADQUARTER_FLAG_MISMATCH"
+)
+
+// IntegrityIssue is a bank whose hierarchy fields disagree with its SWIFT code
+type IntegrityIssue struct {
+	SWIFTCode                    string
+	Type                         string
+	IsHeadquarter                bool
+	HeadquarterSWIFTCode         *string
+	ExpectedIsHeadquarter        bool
+	ExpectedHeadquarterSWIFTCode *string
+}
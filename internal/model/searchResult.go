@@ -0,0 +1,7 @@
+package model
+
+type SearchResult struct {
+	Bank Bank
+	// relevance of the match, between 0 and 1
+	Score float64
+}
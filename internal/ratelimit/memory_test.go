@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+
+	allow := func(t *testing.T, key string) Result {
+		t.Helper()
+
+		result, err := limiter.Allow(ctx, key, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return result
+	}
+
+	t.Run("should allow a burst of the limit", func(t *testing.T) {
+		for i := range limit.Requests {
+			result := allow(t, "client")
+			if !result.Allowed {
+				t.Fatalf("expected request %d to be allowed", i)
+			}
+			if result.Limit != limit.Requests || result.Remaining != limit.Requests-i-1 {
+				t.Errorf("expected limit %d remaining %d, got %+v", limit.Requests, limit.Requests-i-1, result)
+			}
+		}
+	})
+
+	t.Run("should reject an empty bucket", func(t *testing.T) {
+		result := allow(t, "client")
+		if result.Allowed || result.Remaining != 0 {
+			t.Fatalf("expected request to be rejected, got %+v", result)
+		}
+		if result.RetryAfter != time.Second {
+			t.Errorf("expected retry after 1s, got %s", result.RetryAfter)
+		}
+		if result.Reset != limit.Period {
+			t.Errorf("expected reset after %s, got %s", limit.Period, result.Reset)
+		}
+	})
+
+	t.Run("should keep buckets of other keys apart", func(t *testing.T) {
+		if result := allow(t, "other client"); !result.Allowed {
+			t.Errorf("expected request of another key to be allowed, got %+v", result)
+		}
+	})
+
+	t.Run("should refill tokens over the period", func(t *testing.T) {
+		now = now.Add(time.Second)
+		if result := allow(t, "client"); !result.Allowed || result.Remaining != 0 {
+			t.Fatalf("expected refilled request to be allowed, got %+v", result)
+		}
+		if result := allow(t, "client"); result.Allowed {
+			t.Fatalf("expected request to be rejected, got %+v", result)
+		}
+
+		now = now.Add(time.Hour)
+		if result := allow(t, "client"); !result.Allowed || result.Remaining != limit.Requests-1 {
+			t.Errorf("expected bucket to refill up to the limit, got %+v", result)
+		}
+	})
+
+	t.Run("should drop full buckets", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		allow(t, "client")
+
+		if len(limiter.buckets) != 1 {
+			t.Errorf("expected only the used bucket to be kept, got %d buckets", len(limiter.buckets))
+		}
+	})
+
+	t.Run("should not limit unlimited limits", func(t *testing.T) {
+		for range 10 {
+			result, err := limiter.Allow(ctx, "unlimited", Limit{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Allowed {
+				t.Fatal("expected unlimited request to be allowed")
+			}
+		}
+	})
+}
@@ -0,0 +1,38 @@
+// Package ratelimit throttles clients with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit lets a client make Requests requests per Period. A client starts with a full
+// bucket of Requests tokens, each request takes one and they refill evenly over Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Unlimited limits do not throttle anything
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+// Result is the state of a bucket after a request
+type Result struct {
+	Allowed bool
+	// size of the bucket
+	Limit int
+	// tokens left after the request
+	Remaining int
+	// time until the bucket is full again
+	Reset time.Duration
+	// time until the next request can be allowed, zero for allowed requests
+	RetryAfter time.Duration
+}
+
+// Limiter takes a token from the bucket of the key. It is implemented in memory by
+// MemoryLimiter, a limiter shared by many instances can keep buckets in an external store.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
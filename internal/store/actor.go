@@ -0,0 +1,21 @@
+package store
+
+import "context"
+
+type actorCtxKey struct{}
+
+// WithActor returns a context that makes changes recorded in bank history name the actor,
+// e.g. the subject of an API key or token
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// actor recorded with changes made with the context, nil when unknown
+func actorFromContext(ctx context.Context) *string {
+	actor, ok := ctx.Value(actorCtxKey{}).(string)
+	if !ok || actor == "" {
+		return nil
+	}
+
+	return &actor
+}
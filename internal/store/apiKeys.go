@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"strings"
+)
+
+// APIKeyStore keeps hashed API keys in Postgres
+type APIKeyStore struct {
+	db *sql.DB
+}
+
+func (s *APIKeyStore) Create(ctx context.Context, key *model.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, name, keyHash, scopes, expiresAt)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING createdAt
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query, key.ID, key.Name, key.Hash, joinScopes(key.Scopes), key.ExpiresAt).Scan(&key.CreatedAt)
+	return mapPostgresError(err)
+}
+
+func (s *APIKeyStore) GetByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	query := `
+		SELECT id, name, keyHash, scopes, expiresAt, createdAt
+		FROM api_keys
+		WHERE keyHash = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var key model.APIKey
+	var scopes string
+	err := s.db.QueryRowContext(ctx, query, hash).Scan(&key.ID, &key.Name, &key.Hash, &scopes, &key.ExpiresAt, &key.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.APIKey{}, ErrNotFound
+		}
+		return model.APIKey{}, err
+	}
+	key.Scopes = splitScopes(scopes)
+
+	return key, nil
+}
+
+func (s *APIKeyStore) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	query := `
+		SELECT id, name, keyHash, scopes, expiresAt, createdAt
+		FROM api_keys
+		ORDER BY createdAt, id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []model.APIKey
+	for rows.Next() {
+		var key model.APIKey
+		var scopes string
+		if err := rows.Scan(&key.ID, &key.Name, &key.Hash, &scopes, &key.ExpiresAt, &key.CreatedAt); err != nil {
+			return nil, err
+		}
+		key.Scopes = splitScopes(scopes)
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *APIKeyStore) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return deleteAPIKey(ctx, s.db, id)
+}
+
+func deleteAPIKey(ctx context.Context, db *sql.DB, id string) error {
+	result, err := db.ExecContext(ctx, "DELETE FROM api_keys WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// scopes are stored comma separated
+func joinScopes(scopes []string) string {
+	return strings.Join(scopes, ",")
+}
+
+func splitScopes(scopes string) []string {
+	if scopes == "" {
+		return nil
+	}
+
+	return strings.Split(scopes, ",")
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/lib/pq"
+	"time"
 )
 
 type BankStore struct {
@@ -12,31 +14,43 @@ type BankStore struct {
 }
 
 func (s *BankStore) Create(ctx context.Context, bank *model.Bank) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// a branch created together with its headquarter would not see the uncommitted headquarter
+	// and the headquarter would not see the uncommitted branch, neither would link them
+	if err := lockPrefixes(ctx, tx, bank.SWIFTCode); err != nil {
+		return err
+	}
+
+	// concurrent create of the same bank can still pass this check, its insert fails with a unique violation
 	existsQuery := "SELECT EXISTS (SELECT 1 FROM banks WHERE swiftCode = $1)"
 
 	var exists bool
-	err := s.db.QueryRowContext(ctx, existsQuery, bank.SWIFTCode).Scan(&exists)
-	if err != nil {
+	if err := tx.QueryRowContext(ctx, existsQuery, bank.SWIFTCode).Scan(&exists); err != nil {
 		return err
 	}
 	if exists {
 		return ErrAlreadyExists
 	}
 
-	headquarterSwiftCode, err := s.findHeadquarterSwiftCode(ctx, bank.SWIFTCode)
+	headquarterSwiftCode, err := findHeadquarterSwiftCode(ctx, tx, bank.SWIFTCode)
 	if err != nil {
 		return err
 	}
 
 	insertQuery := `
-		INSERT INTO banks (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode)
-		values ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO banks (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
-	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
-	defer cancel()
-
-	_, err = s.db.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		insertQuery,
 		bank.SWIFTCode,
@@ -46,13 +60,70 @@ func (s *BankStore) Create(ctx context.Context, bank *model.Bank) error {
 		bank.CountryName,
 		bank.IsHeadquarter,
 		headquarterSwiftCode,
+		bank.TownName,
+		bank.TimeZone,
+		bank.CodeType,
 	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+
+	if err := recordHistory(ctx, tx, model.OperationInsert, bank.SWIFTCode); err != nil {
+		return err
+	}
+
+	// branches created before their headquarter are linked to it now
+	if bank.IsHeadquarter {
+		adoptedSwiftCodes, err := adoptBranches(ctx, tx, bank.SWIFTCode)
+		if err != nil {
+			return err
+		}
+
+		if err := recordHistory(ctx, tx, model.OperationUpdate, adoptedSwiftCodes...); err != nil {
+			return err
+		}
+	}
+
+	return mapPostgresError(tx.Commit())
+}
+
+// maps constraint violations to store errors, other errors are returned unchanged
+func mapPostgresError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		return ErrAlreadyExists
+	case "23503": // foreign_key_violation
+		return ErrConflict
+	default:
+		return err
+	}
+}
 
+// takes transaction level advisory locks on the 8 character prefixes of the SWIFT codes,
+// serializing changes of a headquarter and its branches. Locks are taken in the order of
+// their keys, so two transactions locking the same prefixes cannot deadlock.
+func lockPrefixes(ctx context.Context, tx *sql.Tx, swiftCodes ...string) error {
+	query := `
+		SELECT pg_advisory_xact_lock(lockKey)
+		FROM (SELECT DISTINCT hashtext(left(code, 8)) AS lockKey FROM unnest($1::text[]) AS code) AS lockKeys
+		ORDER BY lockKey
+	`
+
+	_, err := tx.ExecContext(ctx, query, pq.Array(swiftCodes))
 	return err
 }
 
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 // checks if headquarter of bank already exists in db
-func (s *BankStore) findHeadquarterSwiftCode(ctx context.Context, swiftCode string) (*string, error) {
+func findHeadquarterSwiftCode(ctx context.Context, db queryRower, swiftCode string) (*string, error) {
 	swiftCodeToFind := swiftCode[:8] + "XXX"
 
 	query := `
@@ -62,7 +133,7 @@ func (s *BankStore) findHeadquarterSwiftCode(ctx context.Context, swiftCode stri
 	`
 
 	var foundSwiftCode string
-	err := s.db.QueryRowContext(ctx, query, swiftCodeToFind).Scan(&foundSwiftCode)
+	err := db.QueryRowContext(ctx, query, swiftCodeToFind).Scan(&foundSwiftCode)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -75,7 +146,8 @@ func (s *BankStore) findHeadquarterSwiftCode(ctx context.Context, swiftCode stri
 
 func (s *BankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]model.Bank, error) {
 	headquarterQuery := `
-		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode 
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			createdAt, updatedAt
 		FROM banks
 		WHERE swiftCode = $1
 	`
@@ -86,15 +158,7 @@ func (s *BankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]mod
 	var banks []model.Bank
 
 	var headquarter model.Bank
-	err := s.db.QueryRowContext(ctx, headquarterQuery, swiftCode).Scan(
-		&headquarter.SWIFTCode,
-		&headquarter.Address,
-		&headquarter.BankName,
-		&headquarter.CountryISO2,
-		&headquarter.CountryName,
-		&headquarter.IsHeadquarter,
-		&headquarter.HeadquarterSWIFTCode,
-	)
+	err := scanBank(s.db.QueryRowContext(ctx, headquarterQuery, swiftCode), &headquarter, &headquarter.CreatedAt, &headquarter.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
@@ -105,9 +169,11 @@ func (s *BankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]mod
 	banks = append(banks, headquarter)
 
 	branchesQuery := `
-		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode 
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			createdAt, updatedAt
 		FROM banks
 		WHERE headquarterSwiftCode = $1
+		ORDER BY swiftCode
 	`
 
 	rows, err := s.db.QueryContext(ctx, branchesQuery, swiftCode)
@@ -118,15 +184,7 @@ func (s *BankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]mod
 
 	for rows.Next() {
 		var branch model.Bank
-		err := rows.Scan(
-			&branch.SWIFTCode,
-			&branch.Address,
-			&branch.BankName,
-			&branch.CountryISO2,
-			&branch.CountryName,
-			&branch.IsHeadquarter,
-			&branch.HeadquarterSWIFTCode,
-		)
+		err := scanBank(rows, &branch, &branch.CreatedAt, &branch.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -139,11 +197,14 @@ func (s *BankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]mod
 	return banks, nil
 }
 
-func (s *BankStore) GetAllByCountryISO2(ctx context.Context, countryISO2 string) ([]model.Bank, error) {
+// GetAllBySWIFTCodes returns the stored banks out of the given SWIFT codes, ordered by SWIFT code,
+// without branches of headquarters. Missing banks are left out.
+func (s *BankStore) GetAllBySWIFTCodes(ctx context.Context, swiftCodes []string) ([]model.Bank, error) {
 	query := `
-		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode 
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
 		FROM banks
-		WHERE countryISO2 = $1
+		WHERE swiftCode = ANY($1)
+		ORDER BY swiftCode
 	`
 
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
@@ -151,7 +212,7 @@ func (s *BankStore) GetAllByCountryISO2(ctx context.Context, countryISO2 string)
 
 	var banks []model.Bank
 
-	rows, err := s.db.QueryContext(ctx, query, countryISO2)
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(swiftCodes))
 	if err != nil {
 		return nil, err
 	}
@@ -159,15 +220,106 @@ func (s *BankStore) GetAllByCountryISO2(ctx context.Context, countryISO2 string)
 
 	for rows.Next() {
 		var bank model.Bank
-		err := rows.Scan(
-			&bank.SWIFTCode,
-			&bank.Address,
-			&bank.BankName,
-			&bank.CountryISO2,
-			&bank.CountryName,
-			&bank.IsHeadquarter,
-			&bank.HeadquarterSWIFTCode,
-		)
+		err := scanBank(rows, &bank)
+		if err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return banks, nil
+}
+
+func (s *BankStore) GetAllByCountryISO2(ctx context.Context, countryISO2 string, page Page) ([]model.Bank, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			createdAt, updatedAt
+		FROM banks
+		WHERE countryISO2 = $1 AND swiftCode > $2
+		ORDER BY swiftCode
+		LIMIT $3
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	// LIMIT NULL returns all rows
+	var limit *int
+	if page.Limit > 0 {
+		limit = &page.Limit
+	}
+
+	var banks []model.Bank
+
+	rows, err := s.db.QueryContext(ctx, query, countryISO2, page.After, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bank model.Bank
+		err := scanBank(rows, &bank, &bank.CreatedAt, &bank.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(banks) == 0 && page.After == "" {
+		return nil, ErrNotFound
+	}
+
+	return banks, nil
+}
+
+// GetCountryUpdatedAt returns the time of the latest change of a bank in the country,
+// removals included. It is zero for countries missing from the countries table.
+func (s *BankStore) GetCountryUpdatedAt(ctx context.Context, countryISO2 string) (time.Time, error) {
+	query := `SELECT updatedAt FROM countries WHERE iso2 = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var updatedAt time.Time
+	err := s.db.QueryRowContext(ctx, query, countryISO2).Scan(&updatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, err
+	}
+
+	return updatedAt, nil
+}
+
+// GetAllByInstitutionCode returns banks whose SWIFT code starts with the 4 character
+// institution code, in every country, ordered by SWIFT code
+func (s *BankStore) GetAllByInstitutionCode(ctx context.Context, institutionCode string) ([]model.Bank, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks
+		WHERE left(swiftCode, 4) = $1
+		ORDER BY swiftCode
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var banks []model.Bank
+
+	rows, err := s.db.QueryContext(ctx, query, institutionCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bank model.Bank
+		err := scanBank(rows, &bank)
 		if err != nil {
 			return nil, err
 		}
@@ -184,25 +336,336 @@ func (s *BankStore) GetAllByCountryISO2(ctx context.Context, countryISO2 string)
 	return banks, nil
 }
 
-func (s *BankStore) Delete(ctx context.Context, swiftCode string) error {
+func (s *BankStore) Stream(ctx context.Context, filter BankFilter, fn func(model.Bank) error) error {
 	query := `
-		DELETE FROM banks
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks
+		WHERE ($1 = '' OR countryISO2 = $1) AND ($2::boolean IS NULL OR isHeadquarter = $2)
+		ORDER BY swiftCode
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, filter.CountryISO2, filter.IsHeadquarter)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bank model.Bank
+		if err := scanBank(rows, &bank); err != nil {
+			return err
+		}
+
+		if err := fn(bank); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s *BankStore) Search(ctx context.Context, search SearchQuery) ([]model.SearchResult, error) {
+	query := `
+		WITH search AS (
+			SELECT lower(immutable_unaccent($1)) AS text
+		)
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			greatest(
+				word_similarity(search.text, lower(immutable_unaccent(bankName))),
+				0.8 * word_similarity(search.text, lower(immutable_unaccent(coalesce(townName, '')))),
+				0.6 * word_similarity(search.text, lower(immutable_unaccent(coalesce(address, ''))))
+			) AS score
+		FROM banks, search
+		WHERE search.text <% searchText
+			AND ($2 = '' OR countryISO2 = $2)
+			AND (NOT $3 OR isHeadquarter)
+		ORDER BY score DESC, swiftCode
+		LIMIT $4
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, search.Text, search.CountryISO2, search.HeadquartersOnly, search.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []model.SearchResult
+	for rows.Next() {
+		var result model.SearchResult
+		err := rows.Scan(
+			&result.Bank.SWIFTCode,
+			&result.Bank.Address,
+			&result.Bank.BankName,
+			&result.Bank.CountryISO2,
+			&result.Bank.CountryName,
+			&result.Bank.IsHeadquarter,
+			&result.Bank.HeadquarterSWIFTCode,
+			&result.Bank.TownName,
+			&result.Bank.TimeZone,
+			&result.Bank.CodeType,
+			&result.Score,
+		)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Update replaces the bank stored under swiftCode, the SWIFT code itself can change too.
+// Headquarter links of the bank and of its branches are recomputed in the same transaction.
+func (s *BankStore) Update(ctx context.Context, swiftCode string, bank *model.Bank) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the bank can leave one headquarter and join another
+	if err := lockPrefixes(ctx, tx, swiftCode, bank.SWIFTCode); err != nil {
+		return err
+	}
+
+	lockQuery := `
+		SELECT swiftCode
+		FROM banks
+		WHERE swiftCode = $1
+		FOR UPDATE
+	`
+
+	var lockedSwiftCode string
+	err = tx.QueryRowContext(ctx, lockQuery, swiftCode).Scan(&lockedSwiftCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	// branches with changed headquarter link
+	var relinkedSwiftCodes []string
+
+	renamed := bank.SWIFTCode != swiftCode
+	if renamed {
+		existsQuery := "SELECT EXISTS (SELECT 1 FROM banks WHERE swiftCode = $1)"
+
+		var exists bool
+		if err := tx.QueryRowContext(ctx, existsQuery, bank.SWIFTCode).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return ErrAlreadyExists
+		}
+
+		// in history the old SWIFT code is deleted and the new one inserted
+		if err := recordHistory(ctx, tx, model.OperationDelete, swiftCode); err != nil {
+			return err
+		}
+	}
+
+	// branches are linked again below, if the bank is still their headquarter
+	if renamed || !bank.IsHeadquarter {
+		unlinkQuery := `
+			UPDATE banks
+			SET headquarterSwiftCode = NULL
+			WHERE headquarterSwiftCode = $1
+			RETURNING swiftCode
+		`
+
+		relinkedSwiftCodes, err = querySwiftCodes(ctx, tx, unlinkQuery, swiftCode)
+		if err != nil {
+			return err
+		}
+	}
+
+	var headquarterSwiftCode *string
+	if !bank.IsHeadquarter {
+		headquarterSwiftCode, err = findHeadquarterSwiftCode(ctx, tx, bank.SWIFTCode)
+		if err != nil {
+			return err
+		}
+		// the bank cannot be linked to its own old SWIFT code
+		if headquarterSwiftCode != nil && *headquarterSwiftCode == swiftCode {
+			headquarterSwiftCode = nil
+		}
+	}
+
+	updateQuery := `
+		UPDATE banks
+		SET swiftCode = $2, address = $3, bankName = $4, countryISO2 = $5, countryName = $6, isHeadquarter = $7,
+			headquarterSwiftCode = $8, townName = $9, timeZone = $10, codeType = $11
+		WHERE swiftCode = $1
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		updateQuery,
+		swiftCode,
+		bank.SWIFTCode,
+		bank.Address,
+		bank.BankName,
+		bank.CountryISO2,
+		bank.CountryName,
+		bank.IsHeadquarter,
+		headquarterSwiftCode,
+		bank.TownName,
+		bank.TimeZone,
+		bank.CodeType,
+	)
+	if err != nil {
+		return mapPostgresError(err)
+	}
+
+	if bank.IsHeadquarter {
+		adoptedSwiftCodes, err := adoptBranches(ctx, tx, bank.SWIFTCode)
+		if err != nil {
+			return err
+		}
+		relinkedSwiftCodes = append(relinkedSwiftCodes, adoptedSwiftCodes...)
+	}
+
+	operation := model.OperationUpdate
+	if renamed {
+		operation = model.OperationInsert
+	}
+
+	if err := recordHistory(ctx, tx, operation, bank.SWIFTCode); err != nil {
+		return err
+	}
+
+	if err := recordHistory(ctx, tx, model.OperationUpdate, relinkedSwiftCodes...); err != nil {
+		return err
+	}
+
+	return mapPostgresError(tx.Commit())
+}
+
+// links every branch sharing the 8 character prefix with the headquarter to it,
+// returns SWIFT codes of the adopted branches
+func adoptBranches(ctx context.Context, tx *sql.Tx, headquarterSwiftCode string) ([]string, error) {
+	query := `
+		UPDATE banks
+		SET headquarterSwiftCode = $1
+		WHERE left(swiftCode, 8) = left($1, 8)
+			AND swiftCode <> $1
+			AND NOT isHeadquarter
+			AND headquarterSwiftCode IS DISTINCT FROM $1
+		RETURNING swiftCode
+	`
+
+	return querySwiftCodes(ctx, tx, query, headquarterSwiftCode)
+}
+
+func querySwiftCodes(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var swiftCodes []string
+	for rows.Next() {
+		var swiftCode string
+		if err := rows.Scan(&swiftCode); err != nil {
+			return nil, err
+		}
+		swiftCodes = append(swiftCodes, swiftCode)
+	}
+
+	return swiftCodes, rows.Err()
+}
+
+func (s *BankStore) Delete(ctx context.Context, swiftCode string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockPrefixes(ctx, tx, swiftCode); err != nil {
+		return err
+	}
+
+	lockQuery := `
+		SELECT swiftCode
+		FROM banks
 		WHERE swiftCode = $1
+		FOR UPDATE
 	`
 
-	res, err := s.db.ExecContext(ctx, query, swiftCode)
+	var lockedSwiftCode string
+	err = tx.QueryRowContext(ctx, lockQuery, swiftCode).Scan(&lockedSwiftCode)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
 		return err
 	}
 
-	rows, err := res.RowsAffected()
+	// the last state of the bank is kept in history
+	if err := recordHistory(ctx, tx, model.OperationDelete, swiftCode); err != nil {
+		return err
+	}
+
+	// branches get unlinked by ON DELETE SET NULL
+	branchesQuery := `
+		SELECT swiftCode
+		FROM banks
+		WHERE headquarterSwiftCode = $1
+	`
+
+	branchSwiftCodes, err := querySwiftCodes(ctx, tx, branchesQuery, swiftCode)
 	if err != nil {
 		return err
 	}
 
-	if rows == 0 {
-		return ErrNotFound
+	query := `
+		DELETE FROM banks
+		WHERE swiftCode = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, swiftCode); err != nil {
+		return err
+	}
+
+	if err := recordHistory(ctx, tx, model.OperationUpdate, branchSwiftCodes...); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scans a row selected in the same column order as the banks table,
+// columns selected after codeType are scanned to extra destinations
+func scanBank(row rowScanner, bank *model.Bank, extra ...any) error {
+	return row.Scan(append([]any{
+		&bank.SWIFTCode,
+		&bank.Address,
+		&bank.BankName,
+		&bank.CountryISO2,
+		&bank.CountryName,
+		&bank.IsHeadquarter,
+		&bank.HeadquarterSWIFTCode,
+		&bank.TownName,
+		&bank.TimeZone,
+		&bank.CodeType,
+	}, extra...)...)
 }
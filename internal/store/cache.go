@@ -0,0 +1,294 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"time"
+)
+
+// CacheOptions configures CachedBankStore
+type CacheOptions struct {
+	// maximum number of cached answers, the least recently used one is evicted first
+	Size int
+	// how long an answer is served from the cache
+	TTL time.Duration
+}
+
+// CacheStats counts cache lookups since the cache was created.
+// Evictions are answers dropped for lack of space or after their TTL,
+// invalidations are answers dropped because of a write.
+type CacheStats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+	Entries       int
+	Size          int
+}
+
+// CachedBankStore decorates a bank store with an in-process LRU cache of
+// GetBySWIFTCode and GetAllByCountryISO2 answers, every other method goes
+// straight to the decorated store. Concurrent misses of the same answer
+// share one query. Writes through the cache invalidate answers of the whole
+// 8 character prefix of changed banks, so a headquarter is reloaded when one
+// of its branches changes, together with every cached page of their country.
+// Writes made by other processes are seen once the TTL runs out.
+type CachedBankStore struct {
+	BankStorage
+
+	opts  CacheOptions
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// most recently used entries at the front
+	lru *list.List
+	// bumped by every invalidation, answers loaded before it are not cached
+	generation uint64
+	stats      CacheStats
+}
+
+type cacheEntry struct {
+	key     string
+	expires time.Time
+	banks   []model.Bank
+	// prefix of the bank for GetBySWIFTCode answers, empty for country pages
+	prefix string
+	// country of the page for GetAllByCountryISO2 answers
+	countryISO2 string
+}
+
+// NewCachedStorage returns the storage with its banks cached
+func NewCachedStorage(storage Storage, opts CacheOptions) Storage {
+	storage.Banks = NewCachedBankStore(storage.Banks, opts)
+	return storage
+}
+
+func NewCachedBankStore(banks BankStorage, opts CacheOptions) *CachedBankStore {
+	return &CachedBankStore{
+		BankStorage: banks,
+		opts:        opts,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+	}
+}
+
+// Stats returns the cache counters
+func (c *CachedBankStore) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	stats.Size = c.opts.Size
+	return stats
+}
+
+func (c *CachedBankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]model.Bank, error) {
+	entry := cacheEntry{key: "swift-code/" + swiftCode, prefix: swiftCodePrefix(swiftCode)}
+
+	return c.get(ctx, entry, func(ctx context.Context) ([]model.Bank, error) {
+		return c.BankStorage.GetBySWIFTCode(ctx, swiftCode)
+	})
+}
+
+func (c *CachedBankStore) GetAllByCountryISO2(ctx context.Context, countryISO2 string, page Page) ([]model.Bank, error) {
+	entry := cacheEntry{
+		key:         fmt.Sprintf("country/%s/%s/%d", countryISO2, page.After, page.Limit),
+		countryISO2: countryISO2,
+	}
+
+	return c.get(ctx, entry, func(ctx context.Context) ([]model.Bank, error) {
+		return c.BankStorage.GetAllByCountryISO2(ctx, countryISO2, page)
+	})
+}
+
+func (c *CachedBankStore) Create(ctx context.Context, bank *model.Bank) error {
+	defer c.invalidate([]string{bank.SWIFTCode}, bank.CountryISO2)
+
+	return c.BankStorage.Create(ctx, bank)
+}
+
+func (c *CachedBankStore) Update(ctx context.Context, swiftCode string, bank *model.Bank) error {
+	defer c.invalidate([]string{swiftCode, bank.SWIFTCode}, bank.CountryISO2)
+
+	return c.BankStorage.Update(ctx, swiftCode, bank)
+}
+
+func (c *CachedBankStore) Delete(ctx context.Context, swiftCode string) error {
+	defer c.invalidate([]string{swiftCode})
+
+	return c.BankStorage.Delete(ctx, swiftCode)
+}
+
+func (c *CachedBankStore) CheckIntegrity(ctx context.Context, repair bool) ([]model.IntegrityIssue, error) {
+	if repair {
+		defer c.purge()
+	}
+
+	return c.BankStorage.CheckIntegrity(ctx, repair)
+}
+
+func (c *CachedBankStore) Load(ctx context.Context, source BankSource, opts LoadOptions) (LoadResult, error) {
+	if !opts.DryRun {
+		defer c.purge()
+	}
+
+	return c.BankStorage.Load(ctx, source, opts)
+}
+
+// answers from the cache or loads the answer once for all concurrent misses
+func (c *CachedBankStore) get(ctx context.Context, entry cacheEntry, load func(context.Context) ([]model.Bank, error)) ([]model.Bank, error) {
+	c.mu.Lock()
+	if banks, ok := c.lookup(entry.key); ok {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return cloneBanks(banks), nil
+	}
+	c.stats.Misses++
+	generation := c.generation
+	c.mu.Unlock()
+
+	// misses after an invalidation do not join loads started before it
+	flightKey := fmt.Sprintf("%d/%s", generation, entry.key)
+	value, err, _ := c.group.Do(flightKey, func() (any, error) {
+		// the load is shared, so it must not be canceled with the first caller,
+		// the decorated store bounds it with QueryTimeoutDuration
+		banks, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		if c.generation == generation {
+			entry.banks = banks
+			c.add(entry)
+		}
+		c.mu.Unlock()
+
+		return banks, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cloneBanks(value.([]model.Bank)), nil
+}
+
+// caller holds the lock
+func (c *CachedBankStore) lookup(key string) ([]model.Bank, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.removeElement(element)
+		c.stats.Evictions++
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	return entry.banks, true
+}
+
+// caller holds the lock
+func (c *CachedBankStore) add(entry cacheEntry) {
+	if c.opts.Size <= 0 {
+		return
+	}
+
+	entry.expires = time.Now().Add(c.opts.TTL)
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = &entry
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[entry.key] = c.lru.PushFront(&entry)
+	for c.lru.Len() > c.opts.Size {
+		c.removeElement(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+// caller holds the lock
+func (c *CachedBankStore) removeElement(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
+
+// drops answers sharing a prefix with any of the SWIFT codes, pages containing
+// any of them and every page of the countries. Writes are rare, so all entries
+// are scanned instead of keeping more indexes.
+func (c *CachedBankStore) invalidate(swiftCodes []string, countries ...string) {
+	prefixes := make(map[string]bool, len(swiftCodes))
+	changed := make(map[string]bool, len(swiftCodes))
+	countrySet := make(map[string]bool, len(countries)+len(swiftCodes))
+	for _, swiftCode := range swiftCodes {
+		prefixes[swiftCodePrefix(swiftCode)] = true
+		changed[swiftCode] = true
+		if len(swiftCode) >= 6 {
+			countrySet[swiftCode[4:6]] = true
+		}
+	}
+	for _, countryISO2 := range countries {
+		countrySet[countryISO2] = true
+	}
+	// entries of the other kind leave these fields empty
+	delete(prefixes, "")
+	delete(countrySet, "")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+
+		entry := element.Value.(*cacheEntry)
+		if prefixes[entry.prefix] || countrySet[entry.countryISO2] || containsSwiftCode(entry.banks, changed) {
+			c.removeElement(element)
+			c.stats.Invalidations++
+		}
+
+		element = next
+	}
+}
+
+// drops every answer, used by writes that can change any bank
+func (c *CachedBankStore) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.stats.Invalidations += uint64(c.lru.Len())
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+func containsSwiftCode(banks []model.Bank, swiftCodes map[string]bool) bool {
+	for _, bank := range banks {
+		if swiftCodes[bank.SWIFTCode] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cached banks are shared, callers get copies they are free to modify
+func cloneBanks(banks []model.Bank) []model.Bank {
+	clones := make([]model.Bank, len(banks))
+	for i, bank := range banks {
+		clones[i] = cloneBank(bank)
+	}
+
+	return clones
+}
@@ -0,0 +1,181 @@
+package store_test
+
+import (
+	"context"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store/storetest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachedStorage(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Storage {
+		return store.NewCachedStorage(store.NewMemoryStorage(), store.CacheOptions{Size: 100, TTL: time.Minute})
+	})
+}
+
+// counts queries reaching the decorated store, GetBySWIFTCode waits for release when set
+type countingBankStore struct {
+	store.BankStorage
+	queries atomic.Int64
+	release chan struct{}
+}
+
+func (s *countingBankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]model.Bank, error) {
+	s.queries.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+
+	return s.BankStorage.GetBySWIFTCode(ctx, swiftCode)
+}
+
+func newCachedBankStore(t *testing.T, opts store.CacheOptions) (*store.CachedBankStore, *countingBankStore) {
+	t.Helper()
+
+	inner := &countingBankStore{BankStorage: store.NewMemoryStorage().Banks}
+	for _, swiftCode := range []string{"ABCDPLPWXXX", "ABCDPLPW123", "WXYZPLPWXXX", "WXYZDEFFXXX"} {
+		bank := model.Bank{
+			SWIFTCode:     swiftCode,
+			BankName:      "Bank " + swiftCode,
+			CountryISO2:   swiftCode[4:6],
+			CountryName:   "Country " + swiftCode[4:6],
+			IsHeadquarter: swiftCode[8:] == "XXX",
+		}
+		if err := inner.Create(context.Background(), &bank); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return store.NewCachedBankStore(inner, opts), inner
+}
+
+func getBank(t *testing.T, cache *store.CachedBankStore, swiftCode string) []model.Bank {
+	t.Helper()
+
+	banks, err := cache.GetBySWIFTCode(context.Background(), swiftCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return banks
+}
+
+func checkStats(t *testing.T, cache *store.CachedBankStore, expected store.CacheStats) {
+	t.Helper()
+
+	if stats := cache.Stats(); stats != expected {
+		t.Errorf("expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestCacheHitsAndMisses(t *testing.T) {
+	cache, inner := newCachedBankStore(t, store.CacheOptions{Size: 10, TTL: time.Minute})
+
+	getBank(t, cache, "ABCDPLPWXXX")
+	banks := getBank(t, cache, "ABCDPLPWXXX")
+
+	if queries := inner.queries.Load(); queries != 1 {
+		t.Errorf("expected 1 query, got %d", queries)
+	}
+	checkStats(t, cache, store.CacheStats{Hits: 1, Misses: 1, Entries: 1, Size: 10})
+
+	// answers are copies, changing one does not change the cache
+	banks[0].BankName = "Changed"
+	if banks := getBank(t, cache, "ABCDPLPWXXX"); banks[0].BankName != "Bank ABCDPLPWXXX" {
+		t.Errorf("expected cached bank to stay unchanged, got %s", banks[0].BankName)
+	}
+}
+
+func TestCacheEvictions(t *testing.T) {
+	t.Run("should evict the least recently used answer", func(t *testing.T) {
+		cache, inner := newCachedBankStore(t, store.CacheOptions{Size: 2, TTL: time.Minute})
+
+		getBank(t, cache, "ABCDPLPWXXX")
+		getBank(t, cache, "WXYZPLPWXXX")
+		getBank(t, cache, "ABCDPLPWXXX")
+		getBank(t, cache, "WXYZDEFFXXX")
+		getBank(t, cache, "ABCDPLPWXXX")
+
+		if queries := inner.queries.Load(); queries != 3 {
+			t.Errorf("expected 3 queries, got %d", queries)
+		}
+		checkStats(t, cache, store.CacheStats{Hits: 2, Misses: 3, Evictions: 1, Entries: 2, Size: 2})
+	})
+
+	t.Run("should evict expired answers", func(t *testing.T) {
+		cache, inner := newCachedBankStore(t, store.CacheOptions{Size: 10, TTL: 10 * time.Millisecond})
+
+		getBank(t, cache, "ABCDPLPWXXX")
+		time.Sleep(20 * time.Millisecond)
+		getBank(t, cache, "ABCDPLPWXXX")
+
+		if queries := inner.queries.Load(); queries != 2 {
+			t.Errorf("expected 2 queries, got %d", queries)
+		}
+		checkStats(t, cache, store.CacheStats{Misses: 2, Evictions: 1, Entries: 1, Size: 10})
+	})
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := newCachedBankStore(t, store.CacheOptions{Size: 10, TTL: time.Minute})
+
+	getBank(t, cache, "ABCDPLPWXXX")
+	getBank(t, cache, "WXYZDEFFXXX")
+	if _, err := cache.GetAllByCountryISO2(ctx, "PL", store.Page{}); err != nil {
+		t.Fatal(err)
+	}
+
+	branch := model.Bank{SWIFTCode: "ABCDPLPW456", BankName: "New branch", CountryISO2: "PL", CountryName: "Country PL"}
+	if err := cache.Create(ctx, &branch); err != nil {
+		t.Fatal(err)
+	}
+
+	// the headquarter answer and the country page list the new branch
+	checkStats(t, cache, store.CacheStats{Misses: 3, Invalidations: 2, Entries: 1, Size: 10})
+	if banks := getBank(t, cache, "ABCDPLPWXXX"); len(banks) != 3 {
+		t.Errorf("expected headquarter with 2 branches, got %d banks", len(banks))
+	}
+
+	if err := cache.Delete(ctx, "ABCDPLPW456"); err != nil {
+		t.Fatal(err)
+	}
+	if banks := getBank(t, cache, "ABCDPLPWXXX"); len(banks) != 2 {
+		t.Errorf("expected headquarter with 1 branch, got %d banks", len(banks))
+	}
+
+	// answers of other countries are kept
+	getBank(t, cache, "WXYZDEFFXXX")
+	if stats := cache.Stats(); stats.Hits != 1 {
+		t.Errorf("expected 1 hit, got %d", stats.Hits)
+	}
+}
+
+func TestCacheCoalescesMisses(t *testing.T) {
+	cache, inner := newCachedBankStore(t, store.CacheOptions{Size: 10, TTL: time.Minute})
+	inner.release = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.GetBySWIFTCode(context.Background(), "ABCDPLPWXXX"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// let every lookup miss before the query finishes
+	time.Sleep(50 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	if queries := inner.queries.Load(); queries != 1 {
+		t.Errorf("expected concurrent misses to share 1 query, got %d", queries)
+	}
+}
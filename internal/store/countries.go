@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"strings"
+)
+
+// countriesFile lists ISO 3166 countries with uppercase short names, as written
+// in the SWIFT directory. It is the only copy of the table, SeedCountries writes it
+// to the countries table created by migrations and MemoryCountryStore reads it.
+//
+//go:embed countries.tsv
+var countriesFile string
+
+// countryNames by ISO2 code
+var countryNames = parseCountries(countriesFile)
+
+// CountryName returns the name of the country with the given ISO2 code from countries.tsv,
+// it answers without a query to the countries table, which always holds the same names
+func CountryName(iso2 string) (string, bool) {
+	name, ok := countryNames[iso2]
+	return name, ok
+}
+
+func parseCountries(file string) map[string]string {
+	lines := strings.Split(strings.TrimSpace(file), "\n")
+
+	names := make(map[string]string, len(lines))
+	// the first line is the header
+	for i, line := range lines[1:] {
+		iso2, name, ok := strings.Cut(line, "\t")
+		if !ok || len(iso2) != 2 || name == "" {
+			panic(fmt.Sprintf("countries.tsv: invalid line %d: %q", i+2, line))
+		}
+		names[iso2] = name
+	}
+
+	return names
+}
+
+// SeedCountries makes the countries table match countries.tsv, it is run after the
+// migrations of Postgres and SQLite. Names are updated, countries are never removed.
+func SeedCountries(ctx context.Context, db *sql.DB) error {
+	query := `
+		INSERT INTO countries (iso2, name)
+		VALUES ($1, $2)
+		ON CONFLICT (iso2) DO UPDATE SET name = excluded.name
+	`
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for iso2, name := range countryNames {
+		if _, err := stmt.ExecContext(ctx, iso2, name); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CountryStore reads countries seeded by SeedCountries, it works for Postgres and SQLite
+type CountryStore struct {
+	db *sql.DB
+}
+
+func (s *CountryStore) GetAll(ctx context.Context) ([]model.Country, error) {
+	query := `
+		SELECT countries.iso2, countries.name, count(banks.swiftCode), count(banks.swiftCode) FILTER (WHERE banks.isHeadquarter)
+		FROM countries
+		LEFT JOIN banks ON banks.countryISO2 = countries.iso2
+		GROUP BY countries.iso2, countries.name
+		ORDER BY countries.iso2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var countries []model.Country
+	for rows.Next() {
+		var country model.Country
+		if err := rows.Scan(&country.ISO2, &country.Name, &country.BankCount, &country.HeadquarterCount); err != nil {
+			return nil, err
+		}
+		countries = append(countries, country)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return countries, nil
+}
+
+func (s *CountryStore) GetByISO2(ctx context.Context, iso2 string) (model.Country, error) {
+	query := `
+		SELECT countries.iso2, countries.name, count(banks.swiftCode), count(banks.swiftCode) FILTER (WHERE banks.isHeadquarter)
+		FROM countries
+		LEFT JOIN banks ON banks.countryISO2 = countries.iso2
+		WHERE countries.iso2 = $1
+		GROUP BY countries.iso2, countries.name
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var country model.Country
+	err := s.db.QueryRowContext(ctx, query, iso2).Scan(&country.ISO2, &country.Name, &country.BankCount, &country.HeadquarterCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Country{}, ErrNotFound
+		}
+		return model.Country{}, err
+	}
+
+	return country, nil
+}
@@ -0,0 +1,229 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/lib/pq"
+	"time"
+)
+
+// closes current versions of the banks and stores their state from the banks table as new versions,
+// it has to be called in the transaction that changed the banks. Inserted and updated banks are
+// stamped with the time of the change, so are their headquarters and countries.
+func recordHistory(ctx context.Context, tx *sql.Tx, operation string, swiftCodes ...string) error {
+	if len(swiftCodes) == 0 {
+		return nil
+	}
+
+	// a headquarter is answered together with its branches
+	headquartersQuery := `
+		UPDATE banks
+		SET updatedAt = now()
+		WHERE isHeadquarter AND swiftCode IN (SELECT left(code, 8) || 'XXX' FROM unnest($1::text[]) AS code)
+	`
+
+	if _, err := tx.ExecContext(ctx, headquartersQuery, pq.Array(swiftCodes)); err != nil {
+		return err
+	}
+
+	// current versions still hold the country a moved bank is leaving
+	countriesQuery := `
+		UPDATE countries
+		SET updatedAt = now()
+		WHERE iso2 IN (SELECT countryISO2 FROM banks WHERE swiftCode = ANY($1)
+					   UNION
+					   SELECT countryISO2 FROM banks_history WHERE swiftCode = ANY($1) AND validTo IS NULL)
+	`
+
+	if _, err := tx.ExecContext(ctx, countriesQuery, pq.Array(swiftCodes)); err != nil {
+		return err
+	}
+
+	if operation != model.OperationDelete {
+		stampQuery := `
+			UPDATE banks
+			SET createdAt = CASE WHEN $2 = 'INSERT' THEN now() ELSE createdAt END,
+				updatedAt = now()
+			WHERE swiftCode = ANY($1)
+		`
+
+		if _, err := tx.ExecContext(ctx, stampQuery, pq.Array(swiftCodes), operation); err != nil {
+			return err
+		}
+	}
+
+	closeQuery := `
+		UPDATE banks_history
+		SET validTo = now()
+		WHERE swiftCode = ANY($1) AND validTo IS NULL
+	`
+
+	if _, err := tx.ExecContext(ctx, closeQuery, pq.Array(swiftCodes)); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO banks_history (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType, operation, validFrom, changedBy)
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType, $2, now(), $3
+		FROM banks
+		WHERE swiftCode = ANY($1)
+	`
+
+	_, err := tx.ExecContext(ctx, insertQuery, pq.Array(swiftCodes), operation, actorFromContext(ctx))
+	return err
+}
+
+func (s *BankStore) GetHistory(ctx context.Context, swiftCode string) ([]model.BankVersion, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			operation, validFrom, validTo, changedBy
+		FROM banks_history
+		WHERE swiftCode = $1
+		ORDER BY validFrom, id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, swiftCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []model.BankVersion
+	for rows.Next() {
+		var version model.BankVersion
+		err := rows.Scan(
+			&version.Bank.SWIFTCode,
+			&version.Bank.Address,
+			&version.Bank.BankName,
+			&version.Bank.CountryISO2,
+			&version.Bank.CountryName,
+			&version.Bank.IsHeadquarter,
+			&version.Bank.HeadquarterSWIFTCode,
+			&version.Bank.TownName,
+			&version.Bank.TimeZone,
+			&version.Bank.CodeType,
+			&version.Operation,
+			&version.ValidFrom,
+			&version.ValidTo,
+			&version.ChangedBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return versions, nil
+}
+
+// GetBySWIFTCodeAsOf works like GetBySWIFTCode, but answers with the state at the given time
+func (s *BankStore) GetBySWIFTCodeAsOf(ctx context.Context, swiftCode string, asOf time.Time) ([]model.Bank, error) {
+	headquarterQuery := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks_history
+		WHERE swiftCode = $1
+			AND validFrom <= $2 AND (validTo IS NULL OR validTo > $2)
+			AND operation <> 'DELETE'
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var banks []model.Bank
+
+	var headquarter model.Bank
+	err := scanBank(s.db.QueryRowContext(ctx, headquarterQuery, swiftCode, asOf), &headquarter)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	banks = append(banks, headquarter)
+
+	branchesQuery := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks_history
+		WHERE headquarterSwiftCode = $1
+			AND validFrom <= $2 AND (validTo IS NULL OR validTo > $2)
+			AND operation <> 'DELETE'
+		ORDER BY swiftCode
+	`
+
+	rows, err := s.db.QueryContext(ctx, branchesQuery, swiftCode, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var branch model.Bank
+		if err := scanBank(rows, &branch); err != nil {
+			return nil, err
+		}
+		banks = append(banks, branch)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return banks, nil
+}
+
+// GetAllByCountryISO2AsOf works like GetAllByCountryISO2, but answers with the state at the given time
+func (s *BankStore) GetAllByCountryISO2AsOf(ctx context.Context, countryISO2 string, asOf time.Time, page Page) ([]model.Bank, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks_history
+		WHERE countryISO2 = $1 AND swiftCode > $2
+			AND validFrom <= $3 AND (validTo IS NULL OR validTo > $3)
+			AND operation <> 'DELETE'
+		ORDER BY swiftCode
+		LIMIT $4
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	// LIMIT NULL returns all rows
+	var limit *int
+	if page.Limit > 0 {
+		limit = &page.Limit
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, countryISO2, page.After, asOf, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banks []model.Bank
+	for rows.Next() {
+		var bank model.Bank
+		if err := scanBank(rows, &bank); err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(banks) == 0 && page.After == "" {
+		return nil, ErrNotFound
+	}
+
+	return banks, nil
+}
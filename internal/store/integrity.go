@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/bic"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/lib/pq"
+	"strings"
+)
+
+// compares hierarchy fields of a bank with the ones derived from its SWIFT code,
+// expectedHeadquarterSwiftCode is the existing headquarter with the same 8 character prefix
+func integrityIssues(bank model.Bank, expectedHeadquarterSwiftCode *string) []model.IntegrityIssue {
+	expectedIsHeadquarter := strings.HasSuffix(bank.SWIFTCode, bic.HeadquarterBranchCode)
+	if expectedIsHeadquarter {
+		expectedHeadquarterSwiftCode = nil
+	}
+
+	issue := model.IntegrityIssue{
+		SWIFTCode:                    bank.SWIFTCode,
+		IsHeadquarter:                bank.IsHeadquarter,
+		HeadquarterSWIFTCode:         bank.HeadquarterSWIFTCode,
+		ExpectedIsHeadquarter:        expectedIsHeadquarter,
+		ExpectedHeadquarterSWIFTCode: expectedHeadquarterSwiftCode,
+	}
+
+	var issues []model.IntegrityIssue
+
+	if bank.IsHeadquarter != expectedIsHeadquarter {
+		issue.Type = model.IssueHeadquarterFlagMismatch
+		issues = append(issues, issue)
+	}
+
+	if !equalStrings(bank.HeadquarterSWIFTCode, expectedHeadquarterSwiftCode) {
+		issue.Type = model.IssueWrongHeadquarterLink
+		if bank.HeadquarterSWIFTCode == nil {
+			issue.Type = model.IssueOrphanBranch
+		}
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// CheckIntegrity reports banks whose isHeadquarter or headquarter link disagree with their SWIFT code.
+// With repair set, the reported banks are fixed in the same transaction.
+func (s *BankStore) CheckIntegrity(ctx context.Context, repair bool) ([]model.IntegrityIssue, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// banks cannot change between the check and the repair
+	if repair {
+		if _, err := tx.ExecContext(ctx, "LOCK TABLE banks IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		WITH expected AS (
+			SELECT swiftCode, isHeadquarter, headquarterSwiftCode,
+				right(swiftCode, 3) = 'XXX' AS expectedIsHeadquarter,
+				CASE WHEN right(swiftCode, 3) = 'XXX' THEN NULL ELSE (
+					SELECT headquarter.swiftCode
+					FROM banks headquarter
+					WHERE headquarter.swiftCode = left(banks.swiftCode, 8) || 'XXX'
+				) END AS expectedHeadquarterSwiftCode
+			FROM banks
+		)
+		SELECT swiftCode, isHeadquarter, headquarterSwiftCode, expectedHeadquarterSwiftCode
+		FROM expected
+		WHERE isHeadquarter <> expectedIsHeadquarter
+			OR headquarterSwiftCode IS DISTINCT FROM expectedHeadquarterSwiftCode
+		ORDER BY swiftCode
+	`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []model.IntegrityIssue
+	var swiftCodes []string
+	for rows.Next() {
+		var bank model.Bank
+		var expectedHeadquarterSwiftCode *string
+		if err := rows.Scan(&bank.SWIFTCode, &bank.IsHeadquarter, &bank.HeadquarterSWIFTCode, &expectedHeadquarterSwiftCode); err != nil {
+			return nil, err
+		}
+		issues = append(issues, integrityIssues(bank, expectedHeadquarterSwiftCode)...)
+		swiftCodes = append(swiftCodes, bank.SWIFTCode)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !repair || len(swiftCodes) == 0 {
+		return issues, nil
+	}
+
+	repairQuery := `
+		UPDATE banks
+		SET isHeadquarter = right(swiftCode, 3) = 'XXX',
+			headquarterSwiftCode = CASE WHEN right(swiftCode, 3) = 'XXX' THEN NULL ELSE (
+				SELECT headquarter.swiftCode
+				FROM banks headquarter
+				WHERE headquarter.swiftCode = left(banks.swiftCode, 8) || 'XXX'
+			) END
+		WHERE swiftCode = ANY($1)
+	`
+
+	if _, err := tx.ExecContext(ctx, repairQuery, pq.Array(swiftCodes)); err != nil {
+		return nil, err
+	}
+
+	if err := recordHistory(ctx, tx, model.OperationUpdate, swiftCodes...); err != nil {
+		return nil, err
+	}
+
+	return issues, tx.Commit()
+}
@@ -0,0 +1,309 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/lib/pq"
+	"slices"
+)
+
+// Load upserts banks from the source in a single transaction: they are copied to a staging table,
+// linked to their headquarters and then inserted or updated. Stored banks missing from the source
+// are kept, unless opts.Remove is set. When the same SWIFT code appears more than once, the last bank wins.
+// It runs without QueryTimeoutDuration, loading a full SWIFT directory takes longer.
+func (s *BankStore) Load(ctx context.Context, source BankSource, opts LoadOptions) (LoadResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	defer tx.Rollback()
+
+	// concurrent writers would otherwise see a half loaded directory
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE banks IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return LoadResult{}, err
+	}
+
+	stagingQuery := `
+		CREATE TEMPORARY TABLE banks_staging
+		(
+			position             bigserial,
+			swiftCode            varchar(11)  NOT NULL,
+			address              varchar(255),
+			bankName             varchar(255) NOT NULL,
+			countryISO2          varchar(2)   NOT NULL,
+			countryName          varchar(255) NOT NULL,
+			isHeadquarter        boolean      NOT NULL,
+			headquarterSwiftCode varchar(11)  NULL,
+			townName             varchar(255) NULL,
+			timeZone             varchar(64)  NULL,
+			codeType             varchar(5)   NULL
+		) ON COMMIT DROP
+	`
+
+	if _, err := tx.ExecContext(ctx, stagingQuery); err != nil {
+		return LoadResult{}, err
+	}
+
+	if err := copyBanks(ctx, tx, source); err != nil {
+		return LoadResult{}, err
+	}
+
+	prepareQueries := []string{
+		"CREATE INDEX ON banks_staging (swiftCode)",
+		"ANALYZE banks_staging",
+		// the last bank with a duplicated SWIFT code wins
+		`
+		DELETE FROM banks_staging staged
+		USING banks_staging later
+		WHERE later.swiftCode = staged.swiftCode AND later.position > staged.position
+		`,
+	}
+
+	for _, query := range prepareQueries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return LoadResult{}, err
+		}
+	}
+
+	// stored headquarters are only kept, when missing banks are not removed
+	linkStagedQuery := `
+		UPDATE banks_staging staged
+		SET headquarterSwiftCode = left(staged.swiftCode, 8) || 'XXX'
+		WHERE NOT staged.isHeadquarter
+			AND (
+				EXISTS (SELECT 1 FROM banks_staging headquarter WHERE headquarter.swiftCode = left(staged.swiftCode, 8) || 'XXX')
+				OR (NOT $1 AND EXISTS (SELECT 1 FROM banks headquarter WHERE headquarter.swiftCode = left(staged.swiftCode, 8) || 'XXX'))
+			)
+	`
+
+	if _, err := tx.ExecContext(ctx, linkStagedQuery, opts.Remove); err != nil {
+		return LoadResult{}, err
+	}
+
+	var staged int
+	if err := tx.QueryRowContext(ctx, "SELECT count(*) FROM banks_staging").Scan(&staged); err != nil {
+		return LoadResult{}, err
+	}
+
+	// headquarters are inserted before the banks updated below get linked to them
+	insertQuery := `
+		INSERT INTO banks (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType)
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks_staging staged
+		WHERE NOT EXISTS (SELECT 1 FROM banks WHERE banks.swiftCode = staged.swiftCode)
+		ORDER BY isHeadquarter DESC, swiftCode
+		RETURNING swiftCode
+	`
+
+	insertedSwiftCodes, err := querySwiftCodes(ctx, tx, insertQuery)
+	if err != nil {
+		return LoadResult{}, mapPostgresError(err)
+	}
+	slices.Sort(insertedSwiftCodes)
+
+	changesQuery := `
+		SELECT banks.swiftCode, banks.address, banks.bankName, banks.countryISO2, banks.countryName, banks.isHeadquarter,
+			banks.headquarterSwiftCode, banks.townName, banks.timeZone, banks.codeType,
+			staged.swiftCode, staged.address, staged.bankName, staged.countryISO2, staged.countryName, staged.isHeadquarter,
+			staged.headquarterSwiftCode, staged.townName, staged.timeZone, staged.codeType
+		FROM banks
+		JOIN banks_staging staged ON staged.swiftCode = banks.swiftCode
+		WHERE (banks.address, banks.bankName, banks.countryISO2, banks.countryName, banks.isHeadquarter,
+				banks.headquarterSwiftCode, banks.townName, banks.timeZone, banks.codeType)
+			IS DISTINCT FROM
+				(staged.address, staged.bankName, staged.countryISO2, staged.countryName, staged.isHeadquarter,
+				staged.headquarterSwiftCode, staged.townName, staged.timeZone, staged.codeType)
+		ORDER BY banks.swiftCode
+	`
+
+	changes, err := queryBankChanges(ctx, tx, changesQuery)
+	if err != nil {
+		return LoadResult{}, err
+	}
+
+	updateQuery := `
+		UPDATE banks
+		SET address              = staged.address,
+			bankName             = staged.bankName,
+			countryISO2          = staged.countryISO2,
+			countryName          = staged.countryName,
+			isHeadquarter        = staged.isHeadquarter,
+			headquarterSwiftCode = staged.headquarterSwiftCode,
+			townName             = staged.townName,
+			timeZone             = staged.timeZone,
+			codeType             = staged.codeType
+		FROM banks_staging staged
+		WHERE banks.swiftCode = ANY($1) AND banks.swiftCode = staged.swiftCode
+	`
+
+	updatedSwiftCodes := changedSwiftCodes(changes)
+	if _, err := tx.ExecContext(ctx, updateQuery, pq.Array(updatedSwiftCodes)); err != nil {
+		return LoadResult{}, mapPostgresError(err)
+	}
+
+	var linkedSwiftCodes, removedSwiftCodes []string
+	if opts.Remove {
+		missingQuery := `
+			SELECT swiftCode
+			FROM banks
+			WHERE NOT EXISTS (SELECT 1 FROM banks_staging staged WHERE staged.swiftCode = banks.swiftCode)
+			ORDER BY swiftCode
+		`
+
+		removedSwiftCodes, err = querySwiftCodes(ctx, tx, missingQuery)
+		if err != nil {
+			return LoadResult{}, err
+		}
+
+		// the last state of removed banks is kept in history
+		if err := recordHistory(ctx, tx, model.OperationDelete, removedSwiftCodes...); err != nil {
+			return LoadResult{}, err
+		}
+
+		deleteQuery := `
+			DELETE FROM banks
+			WHERE NOT EXISTS (SELECT 1 FROM banks_staging staged WHERE staged.swiftCode = banks.swiftCode)
+		`
+
+		if _, err := tx.ExecContext(ctx, deleteQuery); err != nil {
+			return LoadResult{}, mapPostgresError(err)
+		}
+	} else {
+		// stored branches missing from the source are adopted by loaded headquarters
+		linkQuery := `
+			UPDATE banks
+			SET headquarterSwiftCode = headquarter.swiftCode
+			FROM banks_staging headquarter
+			WHERE headquarter.isHeadquarter
+				AND left(banks.swiftCode, 8) || 'XXX' = headquarter.swiftCode
+				AND banks.swiftCode <> headquarter.swiftCode
+				AND NOT banks.isHeadquarter
+				AND banks.headquarterSwiftCode IS DISTINCT FROM headquarter.swiftCode
+			RETURNING banks.swiftCode
+		`
+
+		linkedSwiftCodes, err = querySwiftCodes(ctx, tx, linkQuery)
+		if err != nil {
+			return LoadResult{}, err
+		}
+		slices.Sort(linkedSwiftCodes)
+	}
+
+	if err := recordHistory(ctx, tx, model.OperationInsert, insertedSwiftCodes...); err != nil {
+		return LoadResult{}, err
+	}
+
+	if err := recordHistory(ctx, tx, model.OperationUpdate, append(updatedSwiftCodes, linkedSwiftCodes...)...); err != nil {
+		return LoadResult{}, err
+	}
+
+	result := LoadResult{
+		Inserted:  insertedSwiftCodes,
+		Updated:   changes,
+		Removed:   removedSwiftCodes,
+		Linked:    linkedSwiftCodes,
+		Unchanged: staged - len(insertedSwiftCodes) - len(changes),
+	}
+
+	// the deferred rollback discards a dry run
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return LoadResult{}, mapPostgresError(err)
+	}
+
+	return result, nil
+}
+
+// returns pairs of stored and loaded banks, the query selects columns of both
+func queryBankChanges(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]BankChange, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []BankChange
+	for rows.Next() {
+		var change BankChange
+		err := rows.Scan(
+			&change.Before.SWIFTCode,
+			&change.Before.Address,
+			&change.Before.BankName,
+			&change.Before.CountryISO2,
+			&change.Before.CountryName,
+			&change.Before.IsHeadquarter,
+			&change.Before.HeadquarterSWIFTCode,
+			&change.Before.TownName,
+			&change.Before.TimeZone,
+			&change.Before.CodeType,
+			&change.After.SWIFTCode,
+			&change.After.Address,
+			&change.After.BankName,
+			&change.After.CountryISO2,
+			&change.After.CountryName,
+			&change.After.IsHeadquarter,
+			&change.After.HeadquarterSWIFTCode,
+			&change.After.TownName,
+			&change.After.TimeZone,
+			&change.After.CodeType,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+func changedSwiftCodes(changes []BankChange) []string {
+	swiftCodes := make([]string, 0, len(changes))
+	for _, change := range changes {
+		swiftCodes = append(swiftCodes, change.After.SWIFTCode)
+	}
+
+	return swiftCodes
+}
+
+// streams banks from the source to the staging table with COPY
+func copyBanks(ctx context.Context, tx *sql.Tx, source BankSource) error {
+	// COPY quotes column names, unquoted names in CREATE TABLE are lower case
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(
+		"banks_staging",
+		"swiftcode", "address", "bankname", "countryiso2", "countryname", "isheadquarter", "townname", "timezone", "codetype",
+	))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	err = source(func(bank model.Bank) error {
+		_, err := stmt.ExecContext(
+			ctx,
+			bank.SWIFTCode,
+			bank.Address,
+			bank.BankName,
+			bank.CountryISO2,
+			bank.CountryName,
+			bank.IsHeadquarter,
+			bank.TownName,
+			bank.TimeZone,
+			bank.CodeType,
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// flushes the buffered rows
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+
+	return stmt.Close()
+}
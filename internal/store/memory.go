@@ -0,0 +1,762 @@
+package store
+
+import (
+	"context"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/bic"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryBankStore keeps banks in memory, it is safe for concurrent use.
+// Banks are indexed by SWIFT code, by country and by the 8 character prefix
+// shared by a headquarter and its branches.
+type MemoryBankStore struct {
+	mu sync.RWMutex
+
+	banks map[string]model.Bank
+	// SWIFT codes in ascending order, globally, per country, per prefix and per institution code
+	swiftCodes    []string
+	byCountry     map[string][]string
+	byPrefix      map[string][]string
+	byInstitution map[string][]string
+	// searched fields of the banks, prepared when a bank is stored
+	searchDocuments map[string]searchDocument
+
+	// every change appends versions and none are ever dropped, so history grows
+	// with the number of changes for the lifetime of the store, like banks_history
+	// does in the databases. Nothing is pruned because asOf reads can reach any version.
+	history []model.BankVersion
+	// indexes of versions in history
+	historyBySwiftCode map[string][]int
+	historyByCountry   map[string][]int
+	historyByPrefix    map[string][]int
+	currentVersions    map[string]int
+
+	// time of the latest change of a bank per country, removals included
+	countryUpdatedAt map[string]time.Time
+}
+
+func NewMemoryStorage() Storage {
+	banks := NewMemoryBankStore()
+
+	return Storage{
+		Banks:     banks,
+		Countries: &MemoryCountryStore{banks},
+		APIKeys:   NewMemoryAPIKeyStore(),
+	}
+}
+
+func NewMemoryBankStore() *MemoryBankStore {
+	return &MemoryBankStore{
+		banks:              make(map[string]model.Bank),
+		byCountry:          make(map[string][]string),
+		byPrefix:           make(map[string][]string),
+		byInstitution:      make(map[string][]string),
+		searchDocuments:    make(map[string]searchDocument),
+		historyBySwiftCode: make(map[string][]int),
+		historyByCountry:   make(map[string][]int),
+		historyByPrefix:    make(map[string][]int),
+		currentVersions:    make(map[string]int),
+		countryUpdatedAt:   make(map[string]time.Time),
+	}
+}
+
+func (m *MemoryBankStore) Create(ctx context.Context, bank *model.Bank) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.banks[bank.SWIFTCode]; exists {
+		return ErrAlreadyExists
+	}
+
+	createdBank := cloneBank(*bank)
+	createdBank.HeadquarterSWIFTCode = m.findHeadquarterSwiftCode(createdBank.SWIFTCode)
+
+	m.put(createdBank)
+	m.recordHistory(ctx, model.OperationInsert, createdBank.SWIFTCode)
+
+	// branches created before their headquarter are linked to it now
+	if createdBank.IsHeadquarter {
+		m.recordHistory(ctx, model.OperationUpdate, m.adoptBranches(createdBank.SWIFTCode)...)
+	}
+
+	return nil
+}
+
+func (m *MemoryBankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]model.Bank, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	headquarter, ok := m.banks[swiftCode]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	banks := []model.Bank{headquarter}
+	for _, branchSwiftCode := range m.byPrefix[swiftCodePrefix(swiftCode)] {
+		branch := m.banks[branchSwiftCode]
+		if equalStrings(branch.HeadquarterSWIFTCode, &swiftCode) {
+			banks = append(banks, branch)
+		}
+	}
+
+	return banks, nil
+}
+
+func (m *MemoryBankStore) GetAllBySWIFTCodes(ctx context.Context, swiftCodes []string) ([]model.Bank, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var banks []model.Bank
+	for _, swiftCode := range swiftCodes {
+		if bank, ok := m.banks[swiftCode]; ok {
+			banks = append(banks, bank)
+		}
+	}
+
+	slices.SortFunc(banks, func(a, b model.Bank) int {
+		return strings.Compare(a.SWIFTCode, b.SWIFTCode)
+	})
+
+	// duplicated SWIFT codes return the bank once
+	return slices.CompactFunc(banks, func(a, b model.Bank) bool {
+		return a.SWIFTCode == b.SWIFTCode
+	}), nil
+}
+
+func (m *MemoryBankStore) GetAllByCountryISO2(ctx context.Context, countryISO2 string, page Page) ([]model.Bank, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	swiftCodes := pageSwiftCodes(m.byCountry[countryISO2], page)
+
+	if len(swiftCodes) == 0 && page.After == "" {
+		return nil, ErrNotFound
+	}
+
+	banks := make([]model.Bank, 0, len(swiftCodes))
+	for _, swiftCode := range swiftCodes {
+		banks = append(banks, m.banks[swiftCode])
+	}
+
+	return banks, nil
+}
+
+func (m *MemoryBankStore) GetCountryUpdatedAt(ctx context.Context, countryISO2 string) (time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.countryUpdatedAt[countryISO2], nil
+}
+
+func (m *MemoryBankStore) GetAllByInstitutionCode(ctx context.Context, institutionCode string) ([]model.Bank, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	swiftCodes := m.byInstitution[institutionCode]
+
+	if len(swiftCodes) == 0 {
+		return nil, ErrNotFound
+	}
+
+	banks := make([]model.Bank, 0, len(swiftCodes))
+	for _, swiftCode := range swiftCodes {
+		banks = append(banks, m.banks[swiftCode])
+	}
+
+	return banks, nil
+}
+
+func (m *MemoryBankStore) Stream(ctx context.Context, filter BankFilter, fn func(model.Bank) error) error {
+	// banks are copied, so slow consumers do not block writers
+	banks := m.snapshot(filter)
+
+	for _, bank := range banks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(bank); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryBankStore) snapshot(filter BankFilter) []model.Bank {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	swiftCodes := m.swiftCodes
+	if filter.CountryISO2 != "" {
+		swiftCodes = m.byCountry[filter.CountryISO2]
+	}
+
+	banks := make([]model.Bank, 0, len(swiftCodes))
+	for _, swiftCode := range swiftCodes {
+		bank := m.banks[swiftCode]
+		if filter.IsHeadquarter != nil && bank.IsHeadquarter != *filter.IsHeadquarter {
+			continue
+		}
+		banks = append(banks, bank)
+	}
+
+	return banks
+}
+
+func (m *MemoryBankStore) Search(ctx context.Context, search SearchQuery) ([]model.SearchResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	swiftCodes := m.swiftCodes
+	if search.CountryISO2 != "" {
+		swiftCodes = m.byCountry[search.CountryISO2]
+	}
+
+	queryTrigrams := searchTrigrams(search.Text)
+
+	var results []model.SearchResult
+	for _, swiftCode := range swiftCodes {
+		bank := m.banks[swiftCode]
+		if search.HeadquartersOnly && !bank.IsHeadquarter {
+			continue
+		}
+
+		if score, ok := m.searchDocuments[swiftCode].score(queryTrigrams); ok {
+			results = append(results, model.SearchResult{Bank: bank, Score: score})
+		}
+	}
+
+	return rankSearchResults(results, search.Limit), nil
+}
+
+// Update replaces the bank stored under swiftCode, the SWIFT code itself can change too.
+// Headquarter links of the bank and of its branches are recomputed.
+func (m *MemoryBankStore) Update(ctx context.Context, swiftCode string, bank *model.Bank) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.banks[swiftCode]; !ok {
+		return ErrNotFound
+	}
+
+	// branches with changed headquarter link
+	var relinkedSwiftCodes []string
+
+	renamed := bank.SWIFTCode != swiftCode
+	if renamed {
+		if _, exists := m.banks[bank.SWIFTCode]; exists {
+			return ErrAlreadyExists
+		}
+
+		// in history the old SWIFT code is deleted and the new one inserted
+		m.recordHistory(ctx, model.OperationDelete, swiftCode)
+	}
+
+	// branches are linked again below, if the bank is still their headquarter
+	if renamed || !bank.IsHeadquarter {
+		relinkedSwiftCodes = m.unlinkBranches(swiftCode)
+	}
+
+	updatedBank := cloneBank(*bank)
+	updatedBank.CreatedAt = m.banks[swiftCode].CreatedAt
+	updatedBank.HeadquarterSWIFTCode = nil
+	if !updatedBank.IsHeadquarter {
+		headquarterSwiftCode := m.findHeadquarterSwiftCode(updatedBank.SWIFTCode)
+		if headquarterSwiftCode != nil && *headquarterSwiftCode != swiftCode {
+			updatedBank.HeadquarterSWIFTCode = headquarterSwiftCode
+		}
+	}
+
+	m.remove(swiftCode)
+	m.put(updatedBank)
+
+	if updatedBank.IsHeadquarter {
+		relinkedSwiftCodes = append(relinkedSwiftCodes, m.adoptBranches(updatedBank.SWIFTCode)...)
+	}
+
+	if renamed {
+		m.recordHistory(ctx, model.OperationInsert, updatedBank.SWIFTCode)
+	} else {
+		m.recordHistory(ctx, model.OperationUpdate, updatedBank.SWIFTCode)
+	}
+
+	// a branch unlinked from the old SWIFT code can be adopted again by the new one
+	slices.Sort(relinkedSwiftCodes)
+	m.recordHistory(ctx, model.OperationUpdate, slices.Compact(relinkedSwiftCodes)...)
+
+	return nil
+}
+
+func (m *MemoryBankStore) Delete(ctx context.Context, swiftCode string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.banks[swiftCode]; !ok {
+		return ErrNotFound
+	}
+
+	// the last state of the bank is kept in history
+	m.recordHistory(ctx, model.OperationDelete, swiftCode)
+
+	branchSwiftCodes := m.unlinkBranches(swiftCode)
+	m.remove(swiftCode)
+
+	m.recordHistory(ctx, model.OperationUpdate, branchSwiftCodes...)
+
+	return nil
+}
+
+func (m *MemoryBankStore) GetHistory(ctx context.Context, swiftCode string) ([]model.BankVersion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	indexes := m.historyBySwiftCode[swiftCode]
+	if len(indexes) == 0 {
+		return nil, ErrNotFound
+	}
+
+	versions := make([]model.BankVersion, 0, len(indexes))
+	for _, index := range indexes {
+		versions = append(versions, m.history[index])
+	}
+
+	return versions, nil
+}
+
+// GetBySWIFTCodeAsOf works like GetBySWIFTCode, but answers with the state at the given time
+func (m *MemoryBankStore) GetBySWIFTCodeAsOf(ctx context.Context, swiftCode string, asOf time.Time) ([]model.Bank, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	headquarters := m.versionsAsOf(m.historyBySwiftCode[swiftCode], asOf)
+	if len(headquarters) == 0 {
+		return nil, ErrNotFound
+	}
+
+	banks := []model.Bank{headquarters[0]}
+	for _, bank := range m.versionsAsOf(m.historyByPrefix[swiftCodePrefix(swiftCode)], asOf) {
+		if bank.SWIFTCode != swiftCode && equalStrings(bank.HeadquarterSWIFTCode, &swiftCode) {
+			banks = append(banks, bank)
+		}
+	}
+
+	return banks, nil
+}
+
+// GetAllByCountryISO2AsOf works like GetAllByCountryISO2, but answers with the state at the given time
+func (m *MemoryBankStore) GetAllByCountryISO2AsOf(ctx context.Context, countryISO2 string, asOf time.Time, page Page) ([]model.Bank, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var banks []model.Bank
+	for _, bank := range m.versionsAsOf(m.historyByCountry[countryISO2], asOf) {
+		if bank.SWIFTCode > page.After {
+			banks = append(banks, bank)
+		}
+	}
+
+	if len(banks) == 0 && page.After == "" {
+		return nil, ErrNotFound
+	}
+
+	if page.Limit > 0 && len(banks) > page.Limit {
+		banks = banks[:page.Limit]
+	}
+
+	return banks, nil
+}
+
+func (m *MemoryBankStore) CheckIntegrity(ctx context.Context, repair bool) ([]model.IntegrityIssue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var issues []model.IntegrityIssue
+	var repairedSwiftCodes []string
+
+	for _, swiftCode := range m.swiftCodes {
+		bank := m.banks[swiftCode]
+
+		bankIssues := integrityIssues(bank, m.findHeadquarterSwiftCode(swiftCode))
+		if len(bankIssues) == 0 {
+			continue
+		}
+		issues = append(issues, bankIssues...)
+
+		if repair {
+			bank.IsHeadquarter = bankIssues[0].ExpectedIsHeadquarter
+			bank.HeadquarterSWIFTCode = bankIssues[0].ExpectedHeadquarterSWIFTCode
+			m.banks[swiftCode] = bank
+			repairedSwiftCodes = append(repairedSwiftCodes, swiftCode)
+		}
+	}
+
+	m.recordHistory(ctx, model.OperationUpdate, repairedSwiftCodes...)
+
+	return issues, nil
+}
+
+// Load upserts banks from the source, the source is read before locking,
+// so a failing source leaves the storage unchanged
+func (m *MemoryBankStore) Load(ctx context.Context, source BankSource, opts LoadOptions) (LoadResult, error) {
+	// the last bank with a duplicated SWIFT code wins
+	loaded := make(map[string]model.Bank)
+	err := source(func(bank model.Bank) error {
+		loaded[bank.SWIFTCode] = cloneBank(bank)
+		return ctx.Err()
+	})
+	if err != nil {
+		return LoadResult{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result LoadResult
+	var inserted, updated []model.Bank
+
+	for _, swiftCode := range slices.Sorted(maps.Keys(loaded)) {
+		bank := loaded[swiftCode]
+		bank.HeadquarterSWIFTCode = nil
+		if !bank.IsHeadquarter {
+			headquarterSwiftCode := swiftCodePrefix(swiftCode) + bic.HeadquarterBranchCode
+			_, loadedHeadquarter := loaded[headquarterSwiftCode]
+			_, storedHeadquarter := m.banks[headquarterSwiftCode]
+			// stored headquarters are only kept, when missing banks are not removed
+			if headquarterSwiftCode != swiftCode && (loadedHeadquarter || (!opts.Remove && storedHeadquarter)) {
+				bank.HeadquarterSWIFTCode = &headquarterSwiftCode
+			}
+		}
+
+		stored, exists := m.banks[swiftCode]
+		switch {
+		case !exists:
+			inserted = append(inserted, bank)
+			result.Inserted = append(result.Inserted, swiftCode)
+		case !equalBanks(stored, bank):
+			bank.CreatedAt = stored.CreatedAt
+			updated = append(updated, bank)
+			result.Updated = append(result.Updated, BankChange{Before: stored, After: bank})
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if opts.Remove {
+		for _, swiftCode := range m.swiftCodes {
+			if _, ok := loaded[swiftCode]; !ok {
+				result.Removed = append(result.Removed, swiftCode)
+			}
+		}
+	} else {
+		// stored branches missing from the source are adopted by loaded headquarters
+		for _, swiftCode := range m.swiftCodes {
+			branch := m.banks[swiftCode]
+			if _, ok := loaded[swiftCode]; ok || branch.IsHeadquarter {
+				continue
+			}
+
+			headquarterSwiftCode := swiftCodePrefix(swiftCode) + bic.HeadquarterBranchCode
+			if headquarter, ok := loaded[headquarterSwiftCode]; ok && headquarter.IsHeadquarter && !equalStrings(branch.HeadquarterSWIFTCode, &headquarterSwiftCode) {
+				result.Linked = append(result.Linked, swiftCode)
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, bank := range inserted {
+		m.put(bank)
+	}
+
+	for _, bank := range updated {
+		m.remove(bank.SWIFTCode)
+		m.put(bank)
+	}
+
+	for _, swiftCode := range result.Linked {
+		branch := m.banks[swiftCode]
+		headquarterSwiftCode := swiftCodePrefix(swiftCode) + bic.HeadquarterBranchCode
+		branch.HeadquarterSWIFTCode = &headquarterSwiftCode
+		m.banks[swiftCode] = branch
+	}
+
+	// the last state of removed banks is kept in history
+	m.recordHistory(ctx, model.OperationDelete, result.Removed...)
+	for _, swiftCode := range result.Removed {
+		m.remove(swiftCode)
+	}
+
+	m.recordHistory(ctx, model.OperationInsert, result.Inserted...)
+	m.recordHistory(ctx, model.OperationUpdate, changedSwiftCodes(result.Updated)...)
+	m.recordHistory(ctx, model.OperationUpdate, result.Linked...)
+
+	return result, nil
+}
+
+// returns SWIFT code of the existing headquarter with the same 8 character prefix as the bank
+func (m *MemoryBankStore) findHeadquarterSwiftCode(swiftCode string) *string {
+	headquarterSwiftCode := swiftCodePrefix(swiftCode) + bic.HeadquarterBranchCode
+	if headquarterSwiftCode == swiftCode {
+		return nil
+	}
+
+	if _, ok := m.banks[headquarterSwiftCode]; !ok {
+		return nil
+	}
+
+	return &headquarterSwiftCode
+}
+
+// links every branch sharing the 8 character prefix with the headquarter to it,
+// returns SWIFT codes of the adopted branches
+func (m *MemoryBankStore) adoptBranches(headquarterSwiftCode string) []string {
+	var adoptedSwiftCodes []string
+
+	for _, swiftCode := range m.byPrefix[swiftCodePrefix(headquarterSwiftCode)] {
+		branch := m.banks[swiftCode]
+		if swiftCode == headquarterSwiftCode || branch.IsHeadquarter || equalStrings(branch.HeadquarterSWIFTCode, &headquarterSwiftCode) {
+			continue
+		}
+
+		branch.HeadquarterSWIFTCode = &headquarterSwiftCode
+		m.banks[swiftCode] = branch
+		adoptedSwiftCodes = append(adoptedSwiftCodes, swiftCode)
+	}
+
+	return adoptedSwiftCodes
+}
+
+// removes links to the headquarter, returns SWIFT codes of the unlinked branches
+func (m *MemoryBankStore) unlinkBranches(headquarterSwiftCode string) []string {
+	var unlinkedSwiftCodes []string
+
+	for _, swiftCode := range m.byPrefix[swiftCodePrefix(headquarterSwiftCode)] {
+		branch := m.banks[swiftCode]
+		if !equalStrings(branch.HeadquarterSWIFTCode, &headquarterSwiftCode) {
+			continue
+		}
+
+		branch.HeadquarterSWIFTCode = nil
+		m.banks[swiftCode] = branch
+		unlinkedSwiftCodes = append(unlinkedSwiftCodes, swiftCode)
+	}
+
+	return unlinkedSwiftCodes
+}
+
+// stores the bank and adds it to the indexes, the bank must not exist yet
+func (m *MemoryBankStore) put(bank model.Bank) {
+	m.banks[bank.SWIFTCode] = bank
+	m.swiftCodes = insertSorted(m.swiftCodes, bank.SWIFTCode)
+	m.byCountry[bank.CountryISO2] = insertSorted(m.byCountry[bank.CountryISO2], bank.SWIFTCode)
+
+	prefix := swiftCodePrefix(bank.SWIFTCode)
+	m.byPrefix[prefix] = insertSorted(m.byPrefix[prefix], bank.SWIFTCode)
+
+	institutionCode := swiftCodeInstitution(bank.SWIFTCode)
+	m.byInstitution[institutionCode] = insertSorted(m.byInstitution[institutionCode], bank.SWIFTCode)
+
+	m.searchDocuments[bank.SWIFTCode] = newSearchDocument(bank)
+}
+
+// removes the bank and its entries in the indexes
+func (m *MemoryBankStore) remove(swiftCode string) {
+	bank, ok := m.banks[swiftCode]
+	if !ok {
+		return
+	}
+
+	delete(m.banks, swiftCode)
+	delete(m.searchDocuments, swiftCode)
+	m.swiftCodes = removeSorted(m.swiftCodes, swiftCode)
+
+	m.byCountry[bank.CountryISO2] = removeSorted(m.byCountry[bank.CountryISO2], swiftCode)
+	if len(m.byCountry[bank.CountryISO2]) == 0 {
+		delete(m.byCountry, bank.CountryISO2)
+	}
+
+	prefix := swiftCodePrefix(swiftCode)
+	m.byPrefix[prefix] = removeSorted(m.byPrefix[prefix], swiftCode)
+	if len(m.byPrefix[prefix]) == 0 {
+		delete(m.byPrefix, prefix)
+	}
+
+	institutionCode := swiftCodeInstitution(swiftCode)
+	m.byInstitution[institutionCode] = removeSorted(m.byInstitution[institutionCode], swiftCode)
+	if len(m.byInstitution[institutionCode]) == 0 {
+		delete(m.byInstitution, institutionCode)
+	}
+}
+
+// closes current versions of the banks and stores their current state as new versions
+func (m *MemoryBankStore) recordHistory(ctx context.Context, operation string, swiftCodes ...string) {
+	now := time.Now()
+	changedBy := actorFromContext(ctx)
+
+	for _, swiftCode := range swiftCodes {
+		// a headquarter is answered together with its branches
+		headquarterSwiftCode := swiftCodePrefix(swiftCode) + bic.HeadquarterBranchCode
+		if headquarter, ok := m.banks[headquarterSwiftCode]; ok && headquarter.IsHeadquarter {
+			headquarter.UpdatedAt = now
+			m.banks[headquarterSwiftCode] = headquarter
+		}
+
+		if index, ok := m.currentVersions[swiftCode]; ok {
+			// the current version still holds the country a moved bank is leaving
+			m.countryUpdatedAt[m.history[index].Bank.CountryISO2] = now
+			m.history[index].ValidTo = &now
+			delete(m.currentVersions, swiftCode)
+		}
+
+		bank, ok := m.banks[swiftCode]
+		if !ok {
+			continue
+		}
+		m.countryUpdatedAt[bank.CountryISO2] = now
+
+		if operation != model.OperationDelete {
+			if operation == model.OperationInsert {
+				bank.CreatedAt = now
+			}
+			bank.UpdatedAt = now
+			m.banks[swiftCode] = bank
+		}
+
+		// versions do not keep timestamps, like in the other stores
+		version := model.BankVersion{Bank: bank, Operation: operation, ValidFrom: now, ChangedBy: changedBy}
+		version.Bank.CreatedAt = time.Time{}
+		version.Bank.UpdatedAt = time.Time{}
+
+		index := len(m.history)
+		m.history = append(m.history, version)
+
+		m.historyBySwiftCode[swiftCode] = append(m.historyBySwiftCode[swiftCode], index)
+		m.historyByCountry[bank.CountryISO2] = append(m.historyByCountry[bank.CountryISO2], index)
+		prefix := swiftCodePrefix(swiftCode)
+		m.historyByPrefix[prefix] = append(m.historyByPrefix[prefix], index)
+
+		m.currentVersions[swiftCode] = index
+	}
+}
+
+// returns states of banks from the given versions valid at the given time, ordered by SWIFT code
+func (m *MemoryBankStore) versionsAsOf(indexes []int, asOf time.Time) []model.Bank {
+	var banks []model.Bank
+
+	for _, index := range indexes {
+		version := m.history[index]
+		if version.Operation == model.OperationDelete || version.ValidFrom.After(asOf) {
+			continue
+		}
+		if version.ValidTo != nil && !version.ValidTo.After(asOf) {
+			continue
+		}
+		banks = append(banks, version.Bank)
+	}
+
+	slices.SortFunc(banks, func(a, b model.Bank) int {
+		return strings.Compare(a.SWIFTCode, b.SWIFTCode)
+	})
+
+	return banks
+}
+
+// selects SWIFT codes of the page from sorted SWIFT codes
+func pageSwiftCodes(swiftCodes []string, page Page) []string {
+	start, found := slices.BinarySearch(swiftCodes, page.After)
+	if found {
+		start++
+	}
+
+	swiftCodes = swiftCodes[start:]
+	if page.Limit > 0 && len(swiftCodes) > page.Limit {
+		swiftCodes = swiftCodes[:page.Limit]
+	}
+
+	return swiftCodes
+}
+
+// prefix shared by a headquarter and its branches
+func swiftCodePrefix(swiftCode string) string {
+	if len(swiftCode) < 8 {
+		return swiftCode
+	}
+
+	return swiftCode[:8]
+}
+
+// institution code shared by headquarters of a banking group in every country
+func swiftCodeInstitution(swiftCode string) string {
+	if len(swiftCode) < 4 {
+		return swiftCode
+	}
+
+	return swiftCode[:4]
+}
+
+func insertSorted(values []string, value string) []string {
+	index, found := slices.BinarySearch(values, value)
+	if found {
+		return values
+	}
+
+	return slices.Insert(values, index, value)
+}
+
+func removeSorted(values []string, value string) []string {
+	index, found := slices.BinarySearch(values, value)
+	if !found {
+		return values
+	}
+
+	return slices.Delete(values, index, index+1)
+}
+
+// copies the bank, so the stored bank does not share optional fields with the caller
+func cloneBank(bank model.Bank) model.Bank {
+	bank.Address = cloneString(bank.Address)
+	bank.HeadquarterSWIFTCode = cloneString(bank.HeadquarterSWIFTCode)
+	bank.TownName = cloneString(bank.TownName)
+	bank.TimeZone = cloneString(bank.TimeZone)
+	bank.CodeType = cloneString(bank.CodeType)
+
+	return bank
+}
+
+func cloneString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	clone := *value
+	return &clone
+}
+
+func equalStrings(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func equalBanks(a, b model.Bank) bool {
+	return a.SWIFTCode == b.SWIFTCode &&
+		equalStrings(a.Address, b.Address) &&
+		a.BankName == b.BankName &&
+		a.CountryISO2 == b.CountryISO2 &&
+		a.CountryName == b.CountryName &&
+		a.IsHeadquarter == b.IsHeadquarter &&
+		equalStrings(a.HeadquarterSWIFTCode, b.HeadquarterSWIFTCode) &&
+		equalStrings(a.TownName, b.TownName) &&
+		equalStrings(a.TimeZone, b.TimeZone) &&
+		equalStrings(a.CodeType, b.CodeType)
+}
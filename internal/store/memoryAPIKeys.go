@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryAPIKeyStore keeps hashed API keys in memory, it is safe for concurrent use
+type MemoryAPIKeyStore struct {
+	mu   sync.RWMutex
+	keys map[string]model.APIKey
+}
+
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{keys: make(map[string]model.APIKey)}
+}
+
+func (m *MemoryAPIKeyStore) Create(ctx context.Context, key *model.APIKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, stored := range m.keys {
+		if stored.ID == key.ID || stored.Hash == key.Hash {
+			return ErrAlreadyExists
+		}
+	}
+
+	key.CreatedAt = time.Now().UTC()
+	m.keys[key.ID] = cloneAPIKey(*key)
+
+	return nil
+}
+
+func (m *MemoryAPIKeyStore) GetByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, key := range m.keys {
+		if key.Hash == hash {
+			return cloneAPIKey(key), nil
+		}
+	}
+
+	return model.APIKey{}, ErrNotFound
+}
+
+func (m *MemoryAPIKeyStore) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var keys []model.APIKey
+	for _, key := range m.keys {
+		keys = append(keys, cloneAPIKey(key))
+	}
+
+	slices.SortFunc(keys, func(a, b model.APIKey) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return keys, nil
+}
+
+func (m *MemoryAPIKeyStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.keys[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.keys, id)
+
+	return nil
+}
+
+func cloneAPIKey(key model.APIKey) model.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	if key.ExpiresAt != nil {
+		expiresAt := *key.ExpiresAt
+		key.ExpiresAt = &expiresAt
+	}
+
+	return key
+}
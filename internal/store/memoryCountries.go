@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"slices"
+	"strings"
+)
+
+// MemoryCountryStore counts banks of a MemoryBankStore, country names are the
+// ones seeded to the countries table of the SQL storages
+type MemoryCountryStore struct {
+	banks *MemoryBankStore
+}
+
+func (m *MemoryCountryStore) GetAll(ctx context.Context) ([]model.Country, error) {
+	m.banks.mu.RLock()
+	defer m.banks.mu.RUnlock()
+
+	countries := make([]model.Country, 0, len(countryNames))
+	for iso2, name := range countryNames {
+		countries = append(countries, m.banks.country(iso2, name))
+	}
+
+	slices.SortFunc(countries, func(a, b model.Country) int {
+		return strings.Compare(a.ISO2, b.ISO2)
+	})
+
+	return countries, nil
+}
+
+func (m *MemoryCountryStore) GetByISO2(ctx context.Context, iso2 string) (model.Country, error) {
+	name, ok := countryNames[iso2]
+	if !ok {
+		return model.Country{}, ErrNotFound
+	}
+
+	m.banks.mu.RLock()
+	defer m.banks.mu.RUnlock()
+
+	return m.banks.country(iso2, name), nil
+}
+
+// country counts banks of the country, the caller holds the lock
+func (m *MemoryBankStore) country(iso2, name string) model.Country {
+	country := model.Country{ISO2: iso2, Name: name}
+	for _, swiftCode := range m.byCountry[iso2] {
+		country.BankCount++
+		if m.banks[swiftCode].IsHeadquarter {
+			country.HeadquarterCount++
+		}
+	}
+
+	return country
+}
@@ -0,0 +1,13 @@
+package store_test
+
+import (
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store/storetest"
+	"testing"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Storage {
+		return store.NewMemoryStorage()
+	})
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// minimal score of a match, mirrors the default pg_trgm word similarity threshold
+const searchThreshold = 0.6
+
+// weights of the searched fields, bank name matches rank above town and address matches
+const (
+	bankNameWeight = 1.0
+	townNameWeight = 0.8
+	addressWeight  = 0.6
+)
+
+// searched fields of a bank, normalized and split into trigrams of their words,
+// so they are not processed again for every search
+type searchDocument struct {
+	fields []searchField
+	// words of all fields together, the text a bank has to match as a whole
+	words []map[string]bool
+}
+
+type searchField struct {
+	weight float64
+	words  []map[string]bool
+}
+
+func newSearchDocument(bank model.Bank) searchDocument {
+	fields := []struct {
+		value  *string
+		weight float64
+	}{
+		{&bank.BankName, bankNameWeight},
+		{bank.TownName, townNameWeight},
+		{bank.Address, addressWeight},
+	}
+
+	var document searchDocument
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+
+		words := wordTrigrams(normalizeSearchText(*field.value))
+		document.fields = append(document.fields, searchField{weight: field.weight, words: words})
+		document.words = append(document.words, words...)
+	}
+
+	return document
+}
+
+// trigrams of the normalized search text
+func searchTrigrams(text string) map[string]bool {
+	return trigrams(splitWords(normalizeSearchText(text)))
+}
+
+// scores the document against trigrams of the search text the same way as the Postgres
+// implementation does with pg_trgm, returns false if the bank does not match
+func (d searchDocument) score(query map[string]bool) (float64, bool) {
+	if len(query) == 0 {
+		return 0, false
+	}
+
+	if wordSimilarity(query, d.words) < searchThreshold {
+		return 0, false
+	}
+
+	var score float64
+	for _, field := range d.fields {
+		score = max(score, field.weight*wordSimilarity(query, field.words))
+	}
+
+	return score, true
+}
+
+// orders results by descending score and SWIFT code, keeping at most limit of them
+func rankSearchResults(results []model.SearchResult, limit int) []model.SearchResult {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Bank.SWIFTCode < results[j].Bank.SWIFTCode
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// lower-cases the text and strips accents, "Kraków" becomes "krakow"
+func normalizeSearchText(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	folded, _, err := transform.String(t, text)
+	if err != nil {
+		folded = text
+	}
+
+	return strings.ToLower(strings.TrimSpace(folded))
+}
+
+// greatest trigram similarity between the query and any run of consecutive words
+// of the text, an approximation of pg_trgm word_similarity. Runs starting at the same
+// word are grown one word at a time, counting only trigrams new to the run.
+func wordSimilarity(query map[string]bool, words []map[string]bool) float64 {
+	if len(query) == 0 {
+		return 0
+	}
+
+	var best float64
+	for start := range words {
+		extent := make(map[string]bool)
+		common := 0
+
+		for _, word := range words[start:] {
+			for trigram := range word {
+				if extent[trigram] {
+					continue
+				}
+				extent[trigram] = true
+				if query[trigram] {
+					common++
+				}
+			}
+
+			best = max(best, float64(common)/float64(len(query)))
+		}
+
+		if common == len(query) {
+			break
+		}
+	}
+
+	return best
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams of every word of the text
+func wordTrigrams(text string) []map[string]bool {
+	words := splitWords(text)
+
+	result := make([]map[string]bool, 0, len(words))
+	for _, word := range words {
+		result = append(result, trigrams([]string{word}))
+	}
+
+	return result
+}
+
+// pg_trgm style trigrams, every word is padded with two spaces in front and one at the end
+func trigrams(words []string) map[string]bool {
+	result := make(map[string]bool)
+
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			result[string(padded[i:i+3])] = true
+		}
+	}
+
+	return result
+}
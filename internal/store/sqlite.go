@@ -0,0 +1,527 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"time"
+)
+
+// SQLiteBankStore keeps banks in an embedded SQLite database, migrated with cmd/migrations/sqlite.
+// Foreign keys have to be enabled on its connections.
+type SQLiteBankStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteStorage(db *sql.DB) Storage {
+	return Storage{
+		Banks:     &SQLiteBankStore{db},
+		Countries: &CountryStore{db},
+		APIKeys:   &SQLiteAPIKeyStore{db},
+	}
+}
+
+func (s *SQLiteBankStore) Create(ctx context.Context, bank *model.Bank) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existsQuery := "SELECT EXISTS (SELECT 1 FROM banks WHERE swiftCode = $1)"
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, existsQuery, bank.SWIFTCode).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return ErrAlreadyExists
+	}
+
+	headquarterSwiftCode, err := findHeadquarterSwiftCode(ctx, tx, bank.SWIFTCode)
+	if err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO banks (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		insertQuery,
+		bank.SWIFTCode,
+		bank.Address,
+		bank.BankName,
+		bank.CountryISO2,
+		bank.CountryName,
+		bank.IsHeadquarter,
+		headquarterSwiftCode,
+		bank.TownName,
+		bank.TimeZone,
+		bank.CodeType,
+	)
+	if err != nil {
+		return mapSQLiteError(err)
+	}
+
+	if err := recordSQLiteHistory(ctx, tx, model.OperationInsert, bank.SWIFTCode); err != nil {
+		return err
+	}
+
+	// branches created before their headquarter are linked to it now
+	if bank.IsHeadquarter {
+		adoptedSwiftCodes, err := adoptSQLiteBranches(ctx, tx, bank.SWIFTCode)
+		if err != nil {
+			return err
+		}
+
+		if err := recordSQLiteHistory(ctx, tx, model.OperationUpdate, adoptedSwiftCodes...); err != nil {
+			return err
+		}
+	}
+
+	return mapSQLiteError(tx.Commit())
+}
+
+func (s *SQLiteBankStore) GetBySWIFTCode(ctx context.Context, swiftCode string) ([]model.Bank, error) {
+	headquarterQuery := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			createdAt, updatedAt
+		FROM banks
+		WHERE swiftCode = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var headquarter model.Bank
+	err := scanBank(s.db.QueryRowContext(ctx, headquarterQuery, swiftCode), &headquarter, sqliteTime{&headquarter.CreatedAt}, sqliteTime{&headquarter.UpdatedAt})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	branchesQuery := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			createdAt, updatedAt
+		FROM banks
+		WHERE headquarterSwiftCode = $1
+		ORDER BY swiftCode
+	`
+
+	branches, err := querySQLiteBanksWithTimestamps(ctx, s.db, branchesQuery, swiftCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]model.Bank{headquarter}, branches...), nil
+}
+
+func (s *SQLiteBankStore) GetAllBySWIFTCodes(ctx context.Context, swiftCodes []string) ([]model.Bank, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks
+		WHERE swiftCode IN (SELECT value FROM json_each($1))
+		ORDER BY swiftCode
+	`
+
+	swiftCodesJSON, err := json.Marshal(swiftCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return querySQLiteBanks(ctx, s.db, query, string(swiftCodesJSON))
+}
+
+func (s *SQLiteBankStore) GetAllByCountryISO2(ctx context.Context, countryISO2 string, page Page) ([]model.Bank, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			createdAt, updatedAt
+		FROM banks
+		WHERE countryISO2 = $1 AND swiftCode > $2
+		ORDER BY swiftCode
+		LIMIT $3
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	banks, err := querySQLiteBanksWithTimestamps(ctx, s.db, query, countryISO2, page.After, sqliteLimit(page.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(banks) == 0 && page.After == "" {
+		return nil, ErrNotFound
+	}
+
+	return banks, nil
+}
+
+func (s *SQLiteBankStore) GetCountryUpdatedAt(ctx context.Context, countryISO2 string) (time.Time, error) {
+	// countries seeded after the migration adding the column have no time until a bank changes
+	query := `SELECT nullif(updatedAt, '') FROM countries WHERE iso2 = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var updatedAt *string
+	err := s.db.QueryRowContext(ctx, query, countryISO2).Scan(&updatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, err
+	}
+	if updatedAt == nil {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(sqliteTimeFormat, *updatedAt)
+}
+
+func (s *SQLiteBankStore) GetAllByInstitutionCode(ctx context.Context, institutionCode string) ([]model.Bank, error) {
+	// the expression matches the one of idx_institution_code_swift_code
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks
+		WHERE substr(swiftCode, 1, 4) = $1
+		ORDER BY swiftCode
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	banks, err := querySQLiteBanks(ctx, s.db, query, institutionCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(banks) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return banks, nil
+}
+
+func (s *SQLiteBankStore) Stream(ctx context.Context, filter BankFilter, fn func(model.Bank) error) error {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks
+		WHERE ($1 = '' OR countryISO2 = $1) AND ($2 IS NULL OR isHeadquarter = $2)
+		ORDER BY swiftCode
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, filter.CountryISO2, filter.IsHeadquarter)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bank model.Bank
+		if err := scanBank(rows, &bank); err != nil {
+			return err
+		}
+
+		if err := fn(bank); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Search scores the banks in Go, SQLite has no trigram index
+func (s *SQLiteBankStore) Search(ctx context.Context, search SearchQuery) ([]model.SearchResult, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks
+		WHERE ($1 = '' OR countryISO2 = $1) AND (NOT $2 OR isHeadquarter)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	banks, err := querySQLiteBanks(ctx, s.db, query, search.CountryISO2, search.HeadquartersOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	queryTrigrams := searchTrigrams(search.Text)
+
+	var results []model.SearchResult
+	for _, bank := range banks {
+		if score, ok := newSearchDocument(bank).score(queryTrigrams); ok {
+			results = append(results, model.SearchResult{Bank: bank, Score: score})
+		}
+	}
+
+	return rankSearchResults(results, search.Limit), nil
+}
+
+// Update replaces the bank stored under swiftCode, the SWIFT code itself can change too.
+// Headquarter links of the bank and of its branches are recomputed in the same transaction.
+func (s *SQLiteBankStore) Update(ctx context.Context, swiftCode string, bank *model.Bank) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existsQuery := "SELECT EXISTS (SELECT 1 FROM banks WHERE swiftCode = $1)"
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, existsQuery, swiftCode).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	// branches with changed headquarter link
+	var relinkedSwiftCodes []string
+
+	renamed := bank.SWIFTCode != swiftCode
+	if renamed {
+		if err := tx.QueryRowContext(ctx, existsQuery, bank.SWIFTCode).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return ErrAlreadyExists
+		}
+
+		// in history the old SWIFT code is deleted and the new one inserted
+		if err := recordSQLiteHistory(ctx, tx, model.OperationDelete, swiftCode); err != nil {
+			return err
+		}
+	}
+
+	// branches are linked again below, if the bank is still their headquarter
+	if renamed || !bank.IsHeadquarter {
+		unlinkQuery := `
+			UPDATE banks
+			SET headquarterSwiftCode = NULL
+			WHERE headquarterSwiftCode = $1
+			RETURNING swiftCode
+		`
+
+		relinkedSwiftCodes, err = querySwiftCodes(ctx, tx, unlinkQuery, swiftCode)
+		if err != nil {
+			return err
+		}
+	}
+
+	var headquarterSwiftCode *string
+	if !bank.IsHeadquarter {
+		headquarterSwiftCode, err = findHeadquarterSwiftCode(ctx, tx, bank.SWIFTCode)
+		if err != nil {
+			return err
+		}
+		// the bank cannot be linked to its own old SWIFT code
+		if headquarterSwiftCode != nil && *headquarterSwiftCode == swiftCode {
+			headquarterSwiftCode = nil
+		}
+	}
+
+	updateQuery := `
+		UPDATE banks
+		SET swiftCode = $2, address = $3, bankName = $4, countryISO2 = $5, countryName = $6, isHeadquarter = $7,
+			headquarterSwiftCode = $8, townName = $9, timeZone = $10, codeType = $11
+		WHERE swiftCode = $1
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		updateQuery,
+		swiftCode,
+		bank.SWIFTCode,
+		bank.Address,
+		bank.BankName,
+		bank.CountryISO2,
+		bank.CountryName,
+		bank.IsHeadquarter,
+		headquarterSwiftCode,
+		bank.TownName,
+		bank.TimeZone,
+		bank.CodeType,
+	)
+	if err != nil {
+		return mapSQLiteError(err)
+	}
+
+	if bank.IsHeadquarter {
+		adoptedSwiftCodes, err := adoptSQLiteBranches(ctx, tx, bank.SWIFTCode)
+		if err != nil {
+			return err
+		}
+		relinkedSwiftCodes = append(relinkedSwiftCodes, adoptedSwiftCodes...)
+	}
+
+	operation := model.OperationUpdate
+	if renamed {
+		operation = model.OperationInsert
+	}
+
+	if err := recordSQLiteHistory(ctx, tx, operation, bank.SWIFTCode); err != nil {
+		return err
+	}
+
+	if err := recordSQLiteHistory(ctx, tx, model.OperationUpdate, relinkedSwiftCodes...); err != nil {
+		return err
+	}
+
+	return mapSQLiteError(tx.Commit())
+}
+
+func (s *SQLiteBankStore) Delete(ctx context.Context, swiftCode string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existsQuery := "SELECT EXISTS (SELECT 1 FROM banks WHERE swiftCode = $1)"
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, existsQuery, swiftCode).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	// the last state of the bank is kept in history
+	if err := recordSQLiteHistory(ctx, tx, model.OperationDelete, swiftCode); err != nil {
+		return err
+	}
+
+	// branches get unlinked by ON DELETE SET NULL
+	branchesQuery := `
+		SELECT swiftCode
+		FROM banks
+		WHERE headquarterSwiftCode = $1
+	`
+
+	branchSwiftCodes, err := querySwiftCodes(ctx, tx, branchesQuery, swiftCode)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		DELETE FROM banks
+		WHERE swiftCode = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, swiftCode); err != nil {
+		return err
+	}
+
+	if err := recordSQLiteHistory(ctx, tx, model.OperationUpdate, branchSwiftCodes...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// links every branch sharing the 8 character prefix with the headquarter to it,
+// returns SWIFT codes of the adopted branches
+func adoptSQLiteBranches(ctx context.Context, tx *sql.Tx, headquarterSwiftCode string) ([]string, error) {
+	query := `
+		UPDATE banks
+		SET headquarterSwiftCode = $1
+		WHERE substr(swiftCode, 1, 8) = substr($1, 1, 8)
+			AND swiftCode <> $1
+			AND NOT isHeadquarter
+			AND headquarterSwiftCode IS NOT $1
+		RETURNING swiftCode
+	`
+
+	return querySwiftCodes(ctx, tx, query, headquarterSwiftCode)
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func querySQLiteBanks(ctx context.Context, db queryer, query string, args ...any) ([]model.Bank, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banks []model.Bank
+	for rows.Next() {
+		var bank model.Bank
+		if err := scanBank(rows, &bank); err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+
+	return banks, rows.Err()
+}
+
+// works like querySQLiteBanks for queries selecting createdAt and updatedAt after codeType
+func querySQLiteBanksWithTimestamps(ctx context.Context, db queryer, query string, args ...any) ([]model.Bank, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var banks []model.Bank
+	for rows.Next() {
+		var bank model.Bank
+		if err := scanBank(rows, &bank, sqliteTime{&bank.CreatedAt}, sqliteTime{&bank.UpdatedAt}); err != nil {
+			return nil, err
+		}
+		banks = append(banks, bank)
+	}
+
+	return banks, rows.Err()
+}
+
+// maps constraint violations to store errors, other errors are returned unchanged
+func mapSQLiteError(err error) error {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		return ErrAlreadyExists
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return ErrConflict
+	default:
+		return err
+	}
+}
+
+// SQLite returns all rows for a negative LIMIT
+func sqliteLimit(limit int) int {
+	if limit > 0 {
+		return limit
+	}
+
+	return -1
+}
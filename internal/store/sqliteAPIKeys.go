@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"time"
+)
+
+// SQLiteAPIKeyStore keeps hashed API keys in SQLite
+type SQLiteAPIKeyStore struct {
+	db *sql.DB
+}
+
+func (s *SQLiteAPIKeyStore) Create(ctx context.Context, key *model.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, name, keyHash, scopes, expiresAt, createdAt)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	var expiresAt *string
+	if key.ExpiresAt != nil {
+		formatted := formatSQLiteTime(*key.ExpiresAt)
+		expiresAt = &formatted
+	}
+	createdAt := time.Now().UTC()
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, key.ID, key.Name, key.Hash, joinScopes(key.Scopes), expiresAt, formatSQLiteTime(createdAt))
+	if err != nil {
+		return mapSQLiteError(err)
+	}
+	key.CreatedAt = createdAt
+
+	return nil
+}
+
+func (s *SQLiteAPIKeyStore) GetByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	query := `
+		SELECT id, name, keyHash, scopes, expiresAt, createdAt
+		FROM api_keys
+		WHERE keyHash = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	key, err := scanSQLiteAPIKey(s.db.QueryRowContext(ctx, query, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.APIKey{}, ErrNotFound
+		}
+		return model.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func (s *SQLiteAPIKeyStore) GetAll(ctx context.Context) ([]model.APIKey, error) {
+	query := `
+		SELECT id, name, keyHash, scopes, expiresAt, createdAt
+		FROM api_keys
+		ORDER BY createdAt, id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []model.APIKey
+	for rows.Next() {
+		key, err := scanSQLiteAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *SQLiteAPIKeyStore) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return deleteAPIKey(ctx, s.db, id)
+}
+
+func scanSQLiteAPIKey(row rowScanner) (model.APIKey, error) {
+	var key model.APIKey
+	var scopes string
+	var expiresAt *string
+	if err := row.Scan(&key.ID, &key.Name, &key.Hash, &scopes, &expiresAt, sqliteTime{&key.CreatedAt}); err != nil {
+		return model.APIKey{}, err
+	}
+	key.Scopes = splitScopes(scopes)
+
+	if expiresAt != nil {
+		parsedExpiresAt, err := time.Parse(sqliteTimeFormat, *expiresAt)
+		if err != nil {
+			return model.APIKey{}, err
+		}
+		key.ExpiresAt = &parsedExpiresAt
+	}
+
+	return key, nil
+}
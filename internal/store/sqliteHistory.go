@@ -0,0 +1,328 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"time"
+)
+
+// timestamps are stored as fixed width UTC text, so they compare in chronological order
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+// sqliteTime scans a timestamp stored as text
+type sqliteTime struct {
+	t *time.Time
+}
+
+func (s sqliteTime) Scan(value any) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("cannot scan %T as timestamp", value)
+	}
+
+	t, err := time.Parse(sqliteTimeFormat, text)
+	if err != nil {
+		return err
+	}
+	*s.t = t
+
+	return nil
+}
+
+// closes current versions of the banks and stores their state from the banks table as new versions,
+// it has to be called in the transaction that changed the banks. Inserted and updated banks are
+// stamped with the time of the change, so are their headquarters and countries.
+func recordSQLiteHistory(ctx context.Context, tx *sql.Tx, operation string, swiftCodes ...string) error {
+	if len(swiftCodes) == 0 {
+		return nil
+	}
+
+	// SQLite has no arrays, SWIFT codes are passed as a JSON array
+	swiftCodesJSON, err := json.Marshal(swiftCodes)
+	if err != nil {
+		return err
+	}
+
+	now := formatSQLiteTime(time.Now())
+
+	// a headquarter is answered together with its branches
+	headquartersQuery := `
+		UPDATE banks
+		SET updatedAt = $2
+		WHERE isHeadquarter AND swiftCode IN (SELECT substr(value, 1, 8) || 'XXX' FROM json_each($1))
+	`
+
+	if _, err := tx.ExecContext(ctx, headquartersQuery, string(swiftCodesJSON), now); err != nil {
+		return err
+	}
+
+	// current versions still hold the country a moved bank is leaving
+	countriesQuery := `
+		UPDATE countries
+		SET updatedAt = $2
+		WHERE iso2 IN (SELECT countryISO2 FROM banks WHERE swiftCode IN (SELECT value FROM json_each($1))
+					   UNION
+					   SELECT countryISO2 FROM banks_history WHERE swiftCode IN (SELECT value FROM json_each($1)) AND validTo IS NULL)
+	`
+
+	if _, err := tx.ExecContext(ctx, countriesQuery, string(swiftCodesJSON), now); err != nil {
+		return err
+	}
+
+	if operation != model.OperationDelete {
+		stampQuery := `
+			UPDATE banks
+			SET createdAt = CASE WHEN $2 = 'INSERT' THEN $3 ELSE createdAt END,
+				updatedAt = $3
+			WHERE swiftCode IN (SELECT value FROM json_each($1))
+		`
+
+		if _, err := tx.ExecContext(ctx, stampQuery, string(swiftCodesJSON), operation, now); err != nil {
+			return err
+		}
+	}
+
+	closeQuery := `
+		UPDATE banks_history
+		SET validTo = $2
+		WHERE swiftCode IN (SELECT value FROM json_each($1)) AND validTo IS NULL
+	`
+
+	if _, err := tx.ExecContext(ctx, closeQuery, string(swiftCodesJSON), now); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO banks_history (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType, operation, validFrom, changedBy)
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType, $2, $3, $4
+		FROM banks
+		WHERE swiftCode IN (SELECT value FROM json_each($1))
+	`
+
+	_, err = tx.ExecContext(ctx, insertQuery, string(swiftCodesJSON), operation, now, actorFromContext(ctx))
+	return err
+}
+
+func (s *SQLiteBankStore) GetHistory(ctx context.Context, swiftCode string) ([]model.BankVersion, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType,
+			operation, validFrom, validTo, changedBy
+		FROM banks_history
+		WHERE swiftCode = $1
+		ORDER BY validFrom, id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, swiftCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []model.BankVersion
+	for rows.Next() {
+		var version model.BankVersion
+		var validFrom string
+		var validTo *string
+		err := rows.Scan(
+			&version.Bank.SWIFTCode,
+			&version.Bank.Address,
+			&version.Bank.BankName,
+			&version.Bank.CountryISO2,
+			&version.Bank.CountryName,
+			&version.Bank.IsHeadquarter,
+			&version.Bank.HeadquarterSWIFTCode,
+			&version.Bank.TownName,
+			&version.Bank.TimeZone,
+			&version.Bank.CodeType,
+			&version.Operation,
+			&validFrom,
+			&validTo,
+			&version.ChangedBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		version.ValidFrom, err = time.Parse(sqliteTimeFormat, validFrom)
+		if err != nil {
+			return nil, err
+		}
+		if validTo != nil {
+			parsedValidTo, err := time.Parse(sqliteTimeFormat, *validTo)
+			if err != nil {
+				return nil, err
+			}
+			version.ValidTo = &parsedValidTo
+		}
+
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return versions, nil
+}
+
+// GetBySWIFTCodeAsOf works like GetBySWIFTCode, but answers with the state at the given time
+func (s *SQLiteBankStore) GetBySWIFTCodeAsOf(ctx context.Context, swiftCode string, asOf time.Time) ([]model.Bank, error) {
+	headquarterQuery := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks_history
+		WHERE swiftCode = $1
+			AND validFrom <= $2 AND (validTo IS NULL OR validTo > $2)
+			AND operation <> 'DELETE'
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var headquarter model.Bank
+	err := scanBank(s.db.QueryRowContext(ctx, headquarterQuery, swiftCode, formatSQLiteTime(asOf)), &headquarter)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	branchesQuery := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks_history
+		WHERE headquarterSwiftCode = $1
+			AND validFrom <= $2 AND (validTo IS NULL OR validTo > $2)
+			AND operation <> 'DELETE'
+		ORDER BY swiftCode
+	`
+
+	branches, err := querySQLiteBanks(ctx, s.db, branchesQuery, swiftCode, formatSQLiteTime(asOf))
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]model.Bank{headquarter}, branches...), nil
+}
+
+// GetAllByCountryISO2AsOf works like GetAllByCountryISO2, but answers with the state at the given time
+func (s *SQLiteBankStore) GetAllByCountryISO2AsOf(ctx context.Context, countryISO2 string, asOf time.Time, page Page) ([]model.Bank, error) {
+	query := `
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks_history
+		WHERE countryISO2 = $1 AND swiftCode > $2
+			AND validFrom <= $3 AND (validTo IS NULL OR validTo > $3)
+			AND operation <> 'DELETE'
+		ORDER BY swiftCode
+		LIMIT $4
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	banks, err := querySQLiteBanks(ctx, s.db, query, countryISO2, page.After, formatSQLiteTime(asOf), sqliteLimit(page.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(banks) == 0 && page.After == "" {
+		return nil, ErrNotFound
+	}
+
+	return banks, nil
+}
+
+// CheckIntegrity reports banks whose isHeadquarter or headquarter link disagree with their SWIFT code.
+// With repair set, the reported banks are fixed in the same transaction.
+func (s *SQLiteBankStore) CheckIntegrity(ctx context.Context, repair bool) ([]model.IntegrityIssue, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		WITH expected AS (
+			SELECT swiftCode, isHeadquarter, headquarterSwiftCode,
+				substr(swiftCode, -3) = 'XXX' AS expectedIsHeadquarter,
+				CASE WHEN substr(swiftCode, -3) = 'XXX' THEN NULL ELSE (
+					SELECT headquarter.swiftCode
+					FROM banks headquarter
+					WHERE headquarter.swiftCode = substr(banks.swiftCode, 1, 8) || 'XXX'
+				) END AS expectedHeadquarterSwiftCode
+			FROM banks
+		)
+		SELECT swiftCode, isHeadquarter, headquarterSwiftCode, expectedHeadquarterSwiftCode
+		FROM expected
+		WHERE isHeadquarter <> expectedIsHeadquarter
+			OR headquarterSwiftCode IS NOT expectedHeadquarterSwiftCode
+		ORDER BY swiftCode
+	`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []model.IntegrityIssue
+	var swiftCodes []string
+	for rows.Next() {
+		var bank model.Bank
+		var expectedHeadquarterSwiftCode *string
+		if err := rows.Scan(&bank.SWIFTCode, &bank.IsHeadquarter, &bank.HeadquarterSWIFTCode, &expectedHeadquarterSwiftCode); err != nil {
+			return nil, err
+		}
+		issues = append(issues, integrityIssues(bank, expectedHeadquarterSwiftCode)...)
+		swiftCodes = append(swiftCodes, bank.SWIFTCode)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !repair || len(swiftCodes) == 0 {
+		return issues, nil
+	}
+
+	swiftCodesJSON, err := json.Marshal(swiftCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	repairQuery := `
+		UPDATE banks
+		SET isHeadquarter = substr(swiftCode, -3) = 'XXX',
+			headquarterSwiftCode = CASE WHEN substr(swiftCode, -3) = 'XXX' THEN NULL ELSE (
+				SELECT headquarter.swiftCode
+				FROM banks headquarter
+				WHERE headquarter.swiftCode = substr(banks.swiftCode, 1, 8) || 'XXX'
+			) END
+		WHERE swiftCode IN (SELECT value FROM json_each($1))
+	`
+
+	if _, err := tx.ExecContext(ctx, repairQuery, string(swiftCodesJSON)); err != nil {
+		return nil, err
+	}
+
+	if err := recordSQLiteHistory(ctx, tx, model.OperationUpdate, swiftCodes...); err != nil {
+		return nil, err
+	}
+
+	return issues, tx.Commit()
+}
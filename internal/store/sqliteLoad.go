@@ -0,0 +1,249 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"slices"
+)
+
+// Load works like the Postgres one, SQLite has no COPY, so banks are inserted
+// to the staging table with a prepared statement in the same transaction
+func (s *SQLiteBankStore) Load(ctx context.Context, source BankSource, opts LoadOptions) (LoadResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	defer tx.Rollback()
+
+	// temporary tables are created in the transaction, so a rollback drops it too
+	stagingQuery := `
+		CREATE TEMPORARY TABLE banks_staging
+		(
+			position             INTEGER PRIMARY KEY,
+			swiftCode            TEXT    NOT NULL,
+			address              TEXT,
+			bankName             TEXT    NOT NULL,
+			countryISO2          TEXT    NOT NULL,
+			countryName          TEXT    NOT NULL,
+			isHeadquarter        BOOLEAN NOT NULL,
+			headquarterSwiftCode TEXT    NULL,
+			townName             TEXT    NULL,
+			timeZone             TEXT    NULL,
+			codeType             TEXT    NULL
+		)
+	`
+
+	if _, err := tx.ExecContext(ctx, stagingQuery); err != nil {
+		return LoadResult{}, err
+	}
+
+	stageQuery := `
+		INSERT INTO banks_staging (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, townName, timeZone, codeType)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	stmt, err := tx.PrepareContext(ctx, stageQuery)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	defer stmt.Close()
+
+	err = source(func(bank model.Bank) error {
+		_, err := stmt.ExecContext(
+			ctx,
+			bank.SWIFTCode,
+			bank.Address,
+			bank.BankName,
+			bank.CountryISO2,
+			bank.CountryName,
+			bank.IsHeadquarter,
+			bank.TownName,
+			bank.TimeZone,
+			bank.CodeType,
+		)
+		return err
+	})
+	if err != nil {
+		return LoadResult{}, err
+	}
+
+	prepareQueries := []string{
+		"CREATE INDEX temp.idx_staging_swift_code ON banks_staging (swiftCode)",
+		// the last bank with a duplicated SWIFT code wins
+		`
+		DELETE FROM banks_staging
+		WHERE EXISTS (
+			SELECT 1
+			FROM banks_staging later
+			WHERE later.swiftCode = banks_staging.swiftCode AND later.position > banks_staging.position
+		)
+		`,
+	}
+
+	for _, query := range prepareQueries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return LoadResult{}, err
+		}
+	}
+
+	// stored headquarters are only kept, when missing banks are not removed
+	linkStagedQuery := `
+		UPDATE banks_staging
+		SET headquarterSwiftCode = substr(swiftCode, 1, 8) || 'XXX'
+		WHERE NOT isHeadquarter
+			AND (
+				EXISTS (SELECT 1 FROM banks_staging headquarter WHERE headquarter.swiftCode = substr(banks_staging.swiftCode, 1, 8) || 'XXX')
+				OR (NOT $1 AND EXISTS (SELECT 1 FROM banks headquarter WHERE headquarter.swiftCode = substr(banks_staging.swiftCode, 1, 8) || 'XXX'))
+			)
+	`
+
+	if _, err := tx.ExecContext(ctx, linkStagedQuery, opts.Remove); err != nil {
+		return LoadResult{}, err
+	}
+
+	var staged int
+	if err := tx.QueryRowContext(ctx, "SELECT count(*) FROM banks_staging").Scan(&staged); err != nil {
+		return LoadResult{}, err
+	}
+
+	// headquarters are inserted before the banks updated below get linked to them
+	insertQuery := `
+		INSERT INTO banks (swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType)
+		SELECT swiftCode, address, bankName, countryISO2, countryName, isHeadquarter, headquarterSwiftCode, townName, timeZone, codeType
+		FROM banks_staging staged
+		WHERE NOT EXISTS (SELECT 1 FROM banks WHERE banks.swiftCode = staged.swiftCode)
+		ORDER BY isHeadquarter DESC, swiftCode
+		RETURNING swiftCode
+	`
+
+	insertedSwiftCodes, err := querySwiftCodes(ctx, tx, insertQuery)
+	if err != nil {
+		return LoadResult{}, mapSQLiteError(err)
+	}
+	slices.Sort(insertedSwiftCodes)
+
+	changesQuery := `
+		SELECT banks.swiftCode, banks.address, banks.bankName, banks.countryISO2, banks.countryName, banks.isHeadquarter,
+			banks.headquarterSwiftCode, banks.townName, banks.timeZone, banks.codeType,
+			staged.swiftCode, staged.address, staged.bankName, staged.countryISO2, staged.countryName, staged.isHeadquarter,
+			staged.headquarterSwiftCode, staged.townName, staged.timeZone, staged.codeType
+		FROM banks
+		JOIN banks_staging staged ON staged.swiftCode = banks.swiftCode
+		WHERE (banks.address, banks.bankName, banks.countryISO2, banks.countryName, banks.isHeadquarter,
+				banks.headquarterSwiftCode, banks.townName, banks.timeZone, banks.codeType)
+			IS NOT
+				(staged.address, staged.bankName, staged.countryISO2, staged.countryName, staged.isHeadquarter,
+				staged.headquarterSwiftCode, staged.townName, staged.timeZone, staged.codeType)
+		ORDER BY banks.swiftCode
+	`
+
+	changes, err := queryBankChanges(ctx, tx, changesQuery)
+	if err != nil {
+		return LoadResult{}, err
+	}
+
+	updatedSwiftCodes := changedSwiftCodes(changes)
+	updatedSwiftCodesJSON, err := json.Marshal(updatedSwiftCodes)
+	if err != nil {
+		return LoadResult{}, err
+	}
+
+	updateQuery := `
+		UPDATE banks
+		SET address              = staged.address,
+			bankName             = staged.bankName,
+			countryISO2          = staged.countryISO2,
+			countryName          = staged.countryName,
+			isHeadquarter        = staged.isHeadquarter,
+			headquarterSwiftCode = staged.headquarterSwiftCode,
+			townName             = staged.townName,
+			timeZone             = staged.timeZone,
+			codeType             = staged.codeType
+		FROM banks_staging staged
+		WHERE banks.swiftCode IN (SELECT value FROM json_each($1)) AND banks.swiftCode = staged.swiftCode
+	`
+
+	if _, err := tx.ExecContext(ctx, updateQuery, string(updatedSwiftCodesJSON)); err != nil {
+		return LoadResult{}, mapSQLiteError(err)
+	}
+
+	var linkedSwiftCodes, removedSwiftCodes []string
+	if opts.Remove {
+		missingQuery := `
+			SELECT swiftCode
+			FROM banks
+			WHERE NOT EXISTS (SELECT 1 FROM banks_staging staged WHERE staged.swiftCode = banks.swiftCode)
+			ORDER BY swiftCode
+		`
+
+		removedSwiftCodes, err = querySwiftCodes(ctx, tx, missingQuery)
+		if err != nil {
+			return LoadResult{}, err
+		}
+
+		// the last state of removed banks is kept in history
+		if err := recordSQLiteHistory(ctx, tx, model.OperationDelete, removedSwiftCodes...); err != nil {
+			return LoadResult{}, err
+		}
+
+		deleteQuery := `
+			DELETE FROM banks
+			WHERE NOT EXISTS (SELECT 1 FROM banks_staging staged WHERE staged.swiftCode = banks.swiftCode)
+		`
+
+		if _, err := tx.ExecContext(ctx, deleteQuery); err != nil {
+			return LoadResult{}, mapSQLiteError(err)
+		}
+	} else {
+		// stored branches missing from the source are adopted by loaded headquarters
+		linkQuery := `
+			UPDATE banks
+			SET headquarterSwiftCode = headquarter.swiftCode
+			FROM banks_staging headquarter
+			WHERE headquarter.isHeadquarter
+				AND substr(banks.swiftCode, 1, 8) || 'XXX' = headquarter.swiftCode
+				AND banks.swiftCode <> headquarter.swiftCode
+				AND NOT banks.isHeadquarter
+				AND banks.headquarterSwiftCode IS NOT headquarter.swiftCode
+			RETURNING swiftCode
+		`
+
+		linkedSwiftCodes, err = querySwiftCodes(ctx, tx, linkQuery)
+		if err != nil {
+			return LoadResult{}, err
+		}
+		slices.Sort(linkedSwiftCodes)
+	}
+
+	if err := recordSQLiteHistory(ctx, tx, model.OperationInsert, insertedSwiftCodes...); err != nil {
+		return LoadResult{}, err
+	}
+
+	if err := recordSQLiteHistory(ctx, tx, model.OperationUpdate, append(updatedSwiftCodes, linkedSwiftCodes...)...); err != nil {
+		return LoadResult{}, err
+	}
+
+	result := LoadResult{
+		Inserted:  insertedSwiftCodes,
+		Updated:   changes,
+		Removed:   removedSwiftCodes,
+		Linked:    linkedSwiftCodes,
+		Unchanged: staged - len(insertedSwiftCodes) - len(changes),
+	}
+
+	// the deferred rollback discards a dry run
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "DROP TABLE temp.banks_staging"); err != nil {
+		return LoadResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return LoadResult{}, mapSQLiteError(err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package store_test
+
+import (
+	"context"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/db"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store/storetest"
+	"github.com/pressly/goose/v3"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteStorage(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Storage {
+		sqliteDB, err := db.NewSQLite(filepath.Join(t.TempDir(), "swift.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { sqliteDB.Close() })
+
+		if err := goose.SetDialect("sqlite3"); err != nil {
+			t.Fatal(err)
+		}
+		if err := goose.Up(sqliteDB, "../../cmd/migrations/sqlite"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SeedCountries(context.Background(), sqliteDB); err != nil {
+			t.Fatal(err)
+		}
+
+		return store.NewSQLiteStorage(sqliteDB)
+	})
+}
@@ -11,20 +11,96 @@ import (
 var (
 	ErrNotFound          = errors.New("resource not found")
 	ErrAlreadyExists     = errors.New("resource already exists")
+	ErrConflict          = errors.New("resource conflicts with a concurrent change")
 	QueryTimeoutDuration = time.Second * 5
 )
 
+// BankFilter narrows down streamed banks, zero value matches every bank
+type BankFilter struct {
+	CountryISO2   string
+	IsHeadquarter *bool
+}
+
+// Page selects up to Limit banks ordered by SWIFT code, starting right after
+// the After SWIFT code. Zero Limit selects all remaining banks.
+type Page struct {
+	After string
+	Limit int
+}
+
+// SearchQuery matches banks by name, town and address, ignoring case, accents and typos
+type SearchQuery struct {
+	Text             string
+	CountryISO2      string
+	HeadquartersOnly bool
+	Limit            int
+}
+
+// BankSource passes banks to fn one by one and stops at the first error,
+// it lets stores load banks without holding all of them in memory
+type BankSource func(fn func(model.Bank) error) error
+
+// LoadOptions changes how Load treats banks already stored
+type LoadOptions struct {
+	// removes stored banks missing from the source
+	Remove bool
+	// rolls the load back, the result tells what would change
+	DryRun bool
+}
+
+// BankChange is a stored bank updated by a load
+type BankChange struct {
+	Before model.Bank
+	After  model.Bank
+}
+
+// LoadResult lists SWIFT codes of banks changed by a bulk load, in ascending order.
+// Linked lists stored banks missing from the source, linked to headquarters from the source.
+type LoadResult struct {
+	Inserted  []string
+	Updated   []BankChange
+	Removed   []string
+	Linked    []string
+	Unchanged int
+}
+
+// BankStorage is implemented by every bank store
+type BankStorage interface {
+	Create(context.Context, *model.Bank) error
+	GetBySWIFTCode(context.Context, string) ([]model.Bank, error)
+	GetAllBySWIFTCodes(context.Context, []string) ([]model.Bank, error)
+	GetAllByCountryISO2(context.Context, string, Page) ([]model.Bank, error)
+	GetCountryUpdatedAt(context.Context, string) (time.Time, error)
+	GetAllByInstitutionCode(context.Context, string) ([]model.Bank, error)
+	Stream(context.Context, BankFilter, func(model.Bank) error) error
+	Search(context.Context, SearchQuery) ([]model.SearchResult, error)
+	Update(context.Context, string, *model.Bank) error
+	Delete(context.Context, string) error
+	GetHistory(context.Context, string) ([]model.BankVersion, error)
+	GetBySWIFTCodeAsOf(context.Context, string, time.Time) ([]model.Bank, error)
+	GetAllByCountryISO2AsOf(context.Context, string, time.Time, Page) ([]model.Bank, error)
+	CheckIntegrity(context.Context, bool) ([]model.IntegrityIssue, error)
+	Load(context.Context, BankSource, LoadOptions) (LoadResult, error)
+}
+
 type Storage struct {
-	Banks interface {
-		Create(context.Context, *model.Bank) error
-		GetBySWIFTCode(context.Context, string) ([]model.Bank, error)
-		GetAllByCountryISO2(context.Context, string) ([]model.Bank, error)
+	Banks     BankStorage
+	Countries interface {
+		GetAll(context.Context) ([]model.Country, error)
+		GetByISO2(context.Context, string) (model.Country, error)
+	}
+	APIKeys interface {
+		Create(context.Context, *model.APIKey) error
+		GetByHash(context.Context, string) (model.APIKey, error)
+		GetAll(context.Context) ([]model.APIKey, error)
 		Delete(context.Context, string) error
 	}
 }
 
 func NewPostgresStorage(db *sql.DB) Storage {
 	return Storage{
-		Banks: &BankStore{db},
+		Banks:     &BankStore{db},
+		Countries: &CountryStore{db},
+		APIKeys:   &APIKeyStore{db},
 	}
 }
@@ -0,0 +1,916 @@
+// Package storetest checks that a store.Storage implementation behaves like the Postgres one.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/model"
+	"github.com/Ditta1337/RemitlyInternshipTask2025/internal/store"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Factory returns a new, empty storage for every call
+type Factory func(t *testing.T) store.Storage
+
+// Run runs the conformance suite against storages returned by the factory
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, storage store.Storage)
+	}{
+		{"create and get headquarter with branches", testCreateAndGet},
+		{"create does not modify the bank", testCreateDoesNotModifyBank},
+		{"create duplicate", testCreateDuplicate},
+		{"concurrent create", testConcurrentCreate},
+		{"concurrent create of headquarter and branch", testConcurrentHeadquarterAndBranch},
+		{"get missing bank", testGetMissing},
+		{"get many banks", testGetMany},
+		{"list country", testListCountry},
+		{"list institution", testListInstitution},
+		{"stream", testStream},
+		{"update", testUpdate},
+		{"rename headquarter", testRenameHeadquarter},
+		{"delete", testDelete},
+		{"delete headquarter unlinks branches", testDeleteHeadquarter},
+		{"headquarter adopts branches", testHeadquarterAdoptsBranches},
+		{"history", testHistory},
+		{"timestamps", testTimestamps},
+		{"integrity", testIntegrity},
+		{"load", testLoad},
+		{"failed load", testFailedLoad},
+		{"load removing missing banks", testLoadRemove},
+		{"countries", testCountries},
+		{"api keys", testAPIKeys},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStorage(t))
+		})
+	}
+}
+
+func testCreateAndGet(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"), newBank("ABCDPLPW456"), newBank("ABCDPLPW123"), newBank("WXYZPLPW123"))
+
+	banks, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkSWIFTCodes(t, banks, "ABCDPLPWXXX", "ABCDPLPW123", "ABCDPLPW456")
+
+	headquarter := banks[0]
+	if !headquarter.IsHeadquarter || headquarter.HeadquarterSWIFTCode != nil {
+		t.Errorf("expected unlinked headquarter, got %+v", headquarter)
+	}
+	if headquarter.TownName == nil || *headquarter.TownName != "WARSZAWA" {
+		t.Errorf("expected optional fields to be stored, got %+v", headquarter)
+	}
+
+	for _, branch := range banks[1:] {
+		checkHeadquarterLink(t, branch, "ABCDPLPWXXX")
+	}
+
+	banks, err = storage.Banks.GetBySWIFTCode(ctx, "WXYZPLPW123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkSWIFTCodes(t, banks, "WXYZPLPW123")
+	checkHeadquarterLink(t, banks[0], "")
+}
+
+func testCreateDoesNotModifyBank(t *testing.T, storage store.Storage) {
+	create(t, storage, newBank("ABCDPLPWXXX"))
+
+	branch := newBank("ABCDPLPW123")
+	if err := storage.Banks.Create(context.Background(), &branch); err != nil {
+		t.Fatal(err)
+	}
+
+	if branch.HeadquarterSWIFTCode != nil {
+		t.Errorf("expected created bank to stay unchanged, got headquarter link %s", *branch.HeadquarterSWIFTCode)
+	}
+}
+
+func testCreateDuplicate(t *testing.T, storage store.Storage) {
+	create(t, storage, newBank("ABCDPLPWXXX"))
+
+	duplicate := newBank("ABCDPLPWXXX")
+	err := storage.Banks.Create(context.Background(), &duplicate)
+	if !errors.Is(err, store.ErrAlreadyExists) {
+		t.Errorf("expected %v, got %v", store.ErrAlreadyExists, err)
+	}
+}
+
+// only one of the concurrent creates of the same SWIFT code can succeed, the others see a typed error
+func testConcurrentCreate(t *testing.T, storage store.Storage) {
+	const creates = 8
+
+	var wg sync.WaitGroup
+	errs := make([]error, creates)
+	for i := range creates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bank := newBank("ABCDPLPWXXX")
+			errs[i] = storage.Banks.Create(context.Background(), &bank)
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, store.ErrAlreadyExists):
+			t.Errorf("expected %v, got %v", store.ErrAlreadyExists, err)
+		}
+	}
+
+	if created != 1 {
+		t.Errorf("expected exactly one create to succeed, got %d", created)
+	}
+}
+
+// a branch created together with its headquarter ends up linked to it, whichever commits first
+func testConcurrentHeadquarterAndBranch(t *testing.T, storage store.Storage) {
+	const institutions = 8
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2*institutions)
+	for i := range institutions {
+		prefix := "ABC" + string(rune('A'+i)) + "PLPW"
+		for j, swiftCode := range []string{prefix + "XXX", prefix + "123"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				bank := newBank(swiftCode)
+				errs[2*i+j] = storage.Banks.Create(context.Background(), &bank)
+			}()
+		}
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := range institutions {
+		prefix := "ABC" + string(rune('A'+i)) + "PLPW"
+		banks, err := storage.Banks.GetBySWIFTCode(context.Background(), prefix+"123")
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkHeadquarterLink(t, banks[0], prefix+"XXX")
+	}
+}
+
+func testGetMissing(t *testing.T, storage store.Storage) {
+	_, err := storage.Banks.GetBySWIFTCode(context.Background(), "ABCDPLPWXXX")
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v, got %v", store.ErrNotFound, err)
+	}
+}
+
+func testGetMany(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"), newBank("ABCDDEFFXXX"))
+
+	banks, err := storage.Banks.GetAllBySWIFTCodes(ctx, []string{"ABCDPLPWXXX", "MISSPLPWXXX", "ABCDDEFFXXX", "ABCDPLPWXXX"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDDEFFXXX", "ABCDPLPWXXX")
+
+	banks, err = storage.Banks.GetAllBySWIFTCodes(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks)
+}
+
+func testListCountry(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("WXYZPLPWXXX"), newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"), newBank("ABCDDEFFXXX"))
+
+	banks, err := storage.Banks.GetAllByCountryISO2(ctx, "PL", store.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDPLPW123", "ABCDPLPWXXX", "WXYZPLPWXXX")
+
+	banks, err = storage.Banks.GetAllByCountryISO2(ctx, "PL", store.Page{After: "ABCDPLPW123", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDPLPWXXX")
+
+	banks, err = storage.Banks.GetAllByCountryISO2(ctx, "PL", store.Page{After: "WXYZPLPWXXX", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks)
+
+	_, err = storage.Banks.GetAllByCountryISO2(ctx, "US", store.Page{})
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v for country without banks, got %v", store.ErrNotFound, err)
+	}
+}
+
+func testListInstitution(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage,
+		newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"), newBank("ABCDDEFFXXX"), newBank("ABCDDEFF456"),
+		newBank("ABCEPLPWXXX"), newBank("WXYZPLPWXXX"),
+	)
+
+	banks, err := storage.Banks.GetAllByInstitutionCode(ctx, "ABCD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDDEFF456", "ABCDDEFFXXX", "ABCDPLPW123", "ABCDPLPWXXX")
+	checkHeadquarterLink(t, banks[0], "ABCDDEFFXXX")
+
+	// renamed banks move to the other institution
+	renamed := newBank("ABCEDEFF456")
+	if err := storage.Banks.Update(ctx, "ABCDDEFF456", &renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err = storage.Banks.GetAllByInstitutionCode(ctx, "ABCE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCEDEFF456", "ABCEPLPWXXX")
+
+	_, err = storage.Banks.GetAllByInstitutionCode(ctx, "QQQQ")
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v for institution without banks, got %v", store.ErrNotFound, err)
+	}
+}
+
+func testStream(t *testing.T, storage store.Storage) {
+	create(t, storage, newBank("WXYZPLPWXXX"), newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"), newBank("ABCDDEFFXXX"))
+
+	isHeadquarter := true
+	filter := store.BankFilter{CountryISO2: "PL", IsHeadquarter: &isHeadquarter}
+
+	var banks []model.Bank
+	err := storage.Banks.Stream(context.Background(), filter, func(bank model.Bank) error {
+		banks = append(banks, bank)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkSWIFTCodes(t, banks, "ABCDPLPWXXX", "WXYZPLPWXXX")
+}
+
+func testUpdate(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"), newBank("WXYZPLPWXXX"))
+
+	updated := newBank("ABCDPLPW123")
+	updated.BankName = "Updated branch"
+	if err := storage.Banks.Update(ctx, "ABCDPLPW123", &updated); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPW123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if banks[0].BankName != "Updated branch" {
+		t.Errorf("expected updated bank name, got %s", banks[0].BankName)
+	}
+	checkHeadquarterLink(t, banks[0], "ABCDPLPWXXX")
+
+	renamed := newBank("ABCDPLPW456")
+	if err := storage.Banks.Update(ctx, "ABCDPLPW123", &renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPW123"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected old SWIFT code to be gone, got %v", err)
+	}
+
+	taken := newBank("WXYZPLPWXXX")
+	if err := storage.Banks.Update(ctx, "ABCDPLPWXXX", &taken); !errors.Is(err, store.ErrAlreadyExists) {
+		t.Errorf("expected %v when renaming to existing SWIFT code, got %v", store.ErrAlreadyExists, err)
+	}
+
+	missing := newBank("EFGHPLPWXXX")
+	if err := storage.Banks.Update(ctx, "EFGHPLPWXXX", &missing); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v when updating missing bank, got %v", store.ErrNotFound, err)
+	}
+}
+
+func testRenameHeadquarter(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"), newBank("ABCDPLPAXXX"), newBank("ABCDPLPA123"))
+
+	// renamed headquarter leaves branches of its old prefix
+	renamed := newBank("ABCDPLPBXXX")
+	if err := storage.Banks.Update(ctx, "ABCDPLPWXXX", &renamed); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPW123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHeadquarterLink(t, banks[0], "")
+
+	// headquarter becomes a branch of an existing headquarter
+	demoted := newBank("ABCDPLPA456")
+	if err := storage.Banks.Update(ctx, "ABCDPLPBXXX", &demoted); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err = storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPAXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDPLPAXXX", "ABCDPLPA123", "ABCDPLPA456")
+
+	// headquarter becomes a branch with its own prefix, there is no headquarter to link to anymore
+	create(t, storage, newBank("ABCDPLPCXXX"))
+
+	demoted = newBank("ABCDPLPC123")
+	if err := storage.Banks.Update(ctx, "ABCDPLPCXXX", &demoted); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err = storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPC123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHeadquarterLink(t, banks[0], "")
+
+	// headquarter loses its flag without a new SWIFT code, branches are not left linked to a branch
+	create(t, storage, newBank("ABCDPLPDXXX"), newBank("ABCDPLPD123"))
+
+	demoted = newBank("ABCDPLPDXXX")
+	demoted.IsHeadquarter = false
+	if err := storage.Banks.Update(ctx, "ABCDPLPDXXX", &demoted); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err = storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPD123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHeadquarterLink(t, banks[0], "")
+}
+
+func testDelete(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"))
+
+	if err := storage.Banks.Delete(ctx, "ABCDPLPWXXX"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected deleted bank to be gone, got %v", err)
+	}
+
+	if err := storage.Banks.Delete(ctx, "ABCDPLPWXXX"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v when deleting missing bank, got %v", store.ErrNotFound, err)
+	}
+}
+
+func testDeleteHeadquarter(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"))
+
+	if err := storage.Banks.Delete(ctx, "ABCDPLPWXXX"); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPW123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHeadquarterLink(t, banks[0], "")
+}
+
+func testHeadquarterAdoptsBranches(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPW123"), newBank("ABCDPLPW456"), newBank("ABCDPLPAXXX"))
+
+	banks, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPW123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHeadquarterLink(t, banks[0], "")
+
+	create(t, storage, newBank("ABCDPLPWXXX"))
+
+	banks, err = storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDPLPWXXX", "ABCDPLPW123", "ABCDPLPW456")
+}
+
+func testHistory(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPW123"))
+	created := time.Now()
+	create(t, storage, newBank("ABCDPLPWXXX"))
+
+	if err := storage.Banks.Delete(store.WithActor(ctx, "auditor"), "ABCDPLPW123"); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := storage.Banks.GetHistory(ctx, "ABCDPLPW123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedOperations := []string{model.OperationInsert, model.OperationUpdate, model.OperationDelete}
+	if len(versions) != len(expectedOperations) {
+		t.Fatalf("expected %d versions, got %d", len(expectedOperations), len(versions))
+	}
+	for i, version := range versions {
+		if version.Operation != expectedOperations[i] {
+			t.Errorf("expected version %d operation %s, got %s", i, expectedOperations[i], version.Operation)
+		}
+	}
+	checkHeadquarterLink(t, versions[1].Bank, "ABCDPLPWXXX")
+
+	// changes record the actor of their context
+	if versions[0].ChangedBy != nil {
+		t.Errorf("expected version without actor, got %s", *versions[0].ChangedBy)
+	}
+	if changedBy := versions[2].ChangedBy; changedBy == nil || *changedBy != "auditor" {
+		t.Errorf("expected delete to be made by auditor, got %v", changedBy)
+	}
+
+	banks, err := storage.Banks.GetBySWIFTCodeAsOf(ctx, "ABCDPLPW123", created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHeadquarterLink(t, banks[0], "")
+
+	if _, err := storage.Banks.GetBySWIFTCodeAsOf(ctx, "ABCDPLPW123", time.Now()); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected deleted bank to be gone now, got %v", err)
+	}
+
+	banks, err = storage.Banks.GetAllByCountryISO2AsOf(ctx, "PL", created, store.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDPLPW123")
+}
+
+func testTimestamps(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"))
+
+	banks, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	headquarter := banks[0]
+	if headquarter.CreatedAt.IsZero() || !headquarter.UpdatedAt.Equal(headquarter.CreatedAt) {
+		t.Fatalf("expected new bank to be created and updated at the same time, got %v and %v", headquarter.CreatedAt, headquarter.UpdatedAt)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	create(t, storage, newBank("ABCDPLPW123"))
+
+	banks, err = storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDPLPWXXX", "ABCDPLPW123")
+
+	branch := banks[1]
+	if branch.UpdatedAt.IsZero() {
+		t.Errorf("expected branch to have update time")
+	}
+	if !banks[0].UpdatedAt.Equal(branch.UpdatedAt) || !banks[0].CreatedAt.Equal(headquarter.CreatedAt) {
+		t.Errorf("expected new branch to update headquarter at %v, got %v", branch.UpdatedAt, banks[0].UpdatedAt)
+	}
+	headquarter = banks[0]
+
+	time.Sleep(10 * time.Millisecond)
+
+	updated := newBank("ABCDPLPWXXX")
+	updated.BankName = "Updated bank"
+	if err := storage.Banks.Update(ctx, "ABCDPLPWXXX", &updated); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err = storage.Banks.GetAllByCountryISO2(ctx, "PL", store.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, banks, "ABCDPLPW123", "ABCDPLPWXXX")
+
+	if !banks[1].CreatedAt.Equal(headquarter.CreatedAt) {
+		t.Errorf("expected update to keep creation time %v, got %v", headquarter.CreatedAt, banks[1].CreatedAt)
+	}
+	if !banks[1].UpdatedAt.After(headquarter.UpdatedAt) {
+		t.Errorf("expected update time after %v, got %v", headquarter.UpdatedAt, banks[1].UpdatedAt)
+	}
+	if !banks[0].UpdatedAt.Equal(branch.UpdatedAt) {
+		t.Errorf("expected unchanged branch to keep update time %v, got %v", branch.UpdatedAt, banks[0].UpdatedAt)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := storage.Banks.Delete(ctx, "ABCDPLPW123"); err != nil {
+		t.Fatal(err)
+	}
+
+	banks, err = storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !banks[0].UpdatedAt.After(branch.UpdatedAt) {
+		t.Errorf("expected deleted branch to update headquarter after %v, got %v", branch.UpdatedAt, banks[0].UpdatedAt)
+	}
+
+	countryUpdatedAt, err := storage.Banks.GetCountryUpdatedAt(ctx, "PL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !countryUpdatedAt.Equal(banks[0].UpdatedAt) {
+		t.Errorf("expected deleted branch to update country at %v, got %v", banks[0].UpdatedAt, countryUpdatedAt)
+	}
+}
+
+func testIntegrity(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+
+	// headquarter SWIFT code stored as a branch
+	notHeadquarter := newBank("ABCDPLPWXXX")
+	notHeadquarter.IsHeadquarter = false
+	create(t, storage, notHeadquarter, newBank("ABCDPLPW123"))
+
+	issues, err := storage.Banks.CheckIntegrity(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Type != model.IssueHeadquarterFlagMismatch {
+		t.Fatalf("expected headquarter flag mismatch, got %+v", issues)
+	}
+
+	if _, err := storage.Banks.CheckIntegrity(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err = storage.Banks.CheckIntegrity(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues after repair, got %+v", issues)
+	}
+}
+
+func testLoad(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPW123"), newBank("WXYZPLPWXXX"))
+
+	renamed := newBank("WXYZPLPWXXX")
+	renamed.BankName = "Renamed bank"
+	banks := []model.Bank{newBank("ABCDPLPW456"), newBank("WXYZPLPWXXX"), newBank("ABCDPLPWXXX"), renamed}
+
+	result, err := storage.Banks.Load(ctx, sliceSource(banks), store.LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLoadResult(t, result, loadSummary{
+		inserted: "ABCDPLPW456 ABCDPLPWXXX",
+		updated:  "WXYZPLPWXXX",
+		linked:   "ABCDPLPW123",
+	})
+	if result.Updated[0].Before.BankName != "Bank WXYZPLPWXXX" || result.Updated[0].After.BankName != renamed.BankName {
+		t.Errorf("expected the last duplicate to win, got %+v", result.Updated[0])
+	}
+
+	loaded, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, loaded, "ABCDPLPWXXX", "ABCDPLPW123", "ABCDPLPW456")
+
+	// loading the same banks again changes nothing
+	result, err = storage.Banks.Load(ctx, sliceSource(banks), store.LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLoadResult(t, result, loadSummary{unchanged: 3})
+
+	versions, err := storage.Banks.GetHistory(ctx, "ABCDPLPW456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Operation != model.OperationInsert {
+		t.Errorf("expected only the insert in history, got %+v", versions)
+	}
+}
+
+func testFailedLoad(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	errSource := errors.New("broken source")
+
+	source := func(fn func(model.Bank) error) error {
+		if err := fn(newBank("ABCDPLPWXXX")); err != nil {
+			return err
+		}
+		return errSource
+	}
+
+	if _, err := storage.Banks.Load(ctx, source, store.LoadOptions{}); !errors.Is(err, errSource) {
+		t.Fatalf("expected %v, got %v", errSource, err)
+	}
+
+	if _, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected failed load to leave no banks, got %v", err)
+	}
+
+	// the storage stays usable after the rollback
+	if _, err := storage.Banks.Load(ctx, sliceSource([]model.Bank{newBank("ABCDPLPWXXX")}), store.LoadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testLoadRemove(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"), newBank("WXYZPLPWXXX"), newBank("WXYZPLPW123"))
+
+	renamed := newBank("ABCDPLPW123")
+	renamed.BankName = "Renamed bank"
+	banks := []model.Bank{newBank("ABCDPLPWXXX"), renamed, newBank("ABCDPLPW456"), newBank("WXYZPLPW123")}
+	expected := loadSummary{
+		inserted:  "ABCDPLPW456",
+		updated:   "ABCDPLPW123 WXYZPLPW123",
+		removed:   "WXYZPLPWXXX",
+		unchanged: 1,
+	}
+
+	result, err := storage.Banks.Load(ctx, sliceSource(banks), store.LoadOptions{Remove: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLoadResult(t, result, expected)
+
+	if _, err := storage.Banks.GetBySWIFTCode(ctx, "WXYZPLPWXXX"); err != nil {
+		t.Errorf("expected dry run to keep removed bank, got %v", err)
+	}
+	if _, err := storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPW456"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected dry run not to insert bank, got %v", err)
+	}
+
+	result, err = storage.Banks.Load(ctx, sliceSource(banks), store.LoadOptions{Remove: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLoadResult(t, result, expected)
+
+	if _, err := storage.Banks.GetBySWIFTCode(ctx, "WXYZPLPWXXX"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v, got %v", store.ErrNotFound, err)
+	}
+
+	loaded, err := storage.Banks.GetBySWIFTCode(ctx, "WXYZPLPW123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHeadquarterLink(t, loaded[0], "")
+
+	loaded, err = storage.Banks.GetBySWIFTCode(ctx, "ABCDPLPWXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSWIFTCodes(t, loaded, "ABCDPLPWXXX", "ABCDPLPW123", "ABCDPLPW456")
+
+	versions, err := storage.Banks.GetHistory(ctx, "WXYZPLPWXXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := versions[len(versions)-1]; last.Operation != model.OperationDelete {
+		t.Errorf("expected removed bank to end with %s, got %s", model.OperationDelete, last.Operation)
+	}
+}
+
+// SWIFT codes of a load result, separated by spaces
+type loadSummary struct {
+	inserted  string
+	updated   string
+	removed   string
+	linked    string
+	unchanged int
+}
+
+func testAPIKeys(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	readKey := model.APIKey{ID: "key1", Name: "reader", Hash: strings.Repeat("a", 64), Scopes: []string{model.ScopeRead}}
+	writeKey := model.APIKey{
+		ID:        "key2",
+		Name:      "writer",
+		Hash:      strings.Repeat("b", 64),
+		Scopes:    []string{model.ScopeRead, model.ScopeWrite},
+		ExpiresAt: &expiresAt,
+	}
+	for _, key := range []*model.APIKey{&readKey, &writeKey} {
+		if err := storage.APIKeys.Create(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+		if key.CreatedAt.IsZero() {
+			t.Errorf("expected created key %s to get createdAt", key.ID)
+		}
+	}
+
+	duplicate := model.APIKey{ID: "key1", Name: "duplicate", Hash: strings.Repeat("c", 64), Scopes: []string{model.ScopeRead}}
+	if err := storage.APIKeys.Create(ctx, &duplicate); !errors.Is(err, store.ErrAlreadyExists) {
+		t.Errorf("expected %v for duplicate id, got %v", store.ErrAlreadyExists, err)
+	}
+
+	key, err := storage.APIKeys.GetByHash(ctx, writeKey.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != "key2" || key.Name != "writer" || !slices.Contains(key.Scopes, model.ScopeWrite) || slices.Contains(key.Scopes, model.ScopeAdmin) {
+		t.Errorf("expected writer key, got %+v", key)
+	}
+	if key.ExpiresAt == nil || !key.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("expected key to expire at %s, got %v", expiresAt, key.ExpiresAt)
+	}
+
+	if _, err := storage.APIKeys.GetByHash(ctx, strings.Repeat("d", 64)); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v for unknown hash, got %v", store.ErrNotFound, err)
+	}
+
+	keys, err := storage.APIKeys.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].ID != "key1" || keys[1].ID != "key2" {
+		t.Fatalf("expected keys key1 and key2, got %+v", keys)
+	}
+	if keys[0].ExpiresAt != nil {
+		t.Errorf("expected key1 not to expire, got %v", keys[0].ExpiresAt)
+	}
+
+	if err := storage.APIKeys.Delete(ctx, "key1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.APIKeys.Delete(ctx, "key1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v for deleted key, got %v", store.ErrNotFound, err)
+	}
+	if _, err := storage.APIKeys.GetByHash(ctx, readKey.Hash); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected deleted key to be gone, got %v", err)
+	}
+}
+
+func testCountries(t *testing.T, storage store.Storage) {
+	ctx := context.Background()
+	create(t, storage, newBank("ABCDPLPWXXX"), newBank("ABCDPLPW123"), newBank("WXYZPLPWXXX"), newBank("ABCDDEFFXXX"))
+
+	country, err := storage.Countries.GetByISO2(ctx, "PL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := model.Country{ISO2: "PL", Name: "POLAND", BankCount: 3, HeadquarterCount: 2}
+	if country != expected {
+		t.Errorf("expected %+v, got %+v", expected, country)
+	}
+
+	_, err = storage.Countries.GetByISO2(ctx, "QQ")
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected %v for unknown country, got %v", store.ErrNotFound, err)
+	}
+
+	countries, err := storage.Countries.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every ISO 3166 country is listed, with or without banks
+	if len(countries) != 250 {
+		t.Fatalf("expected 250 countries, got %d", len(countries))
+	}
+
+	for i, country := range countries {
+		if i > 0 && countries[i-1].ISO2 >= country.ISO2 {
+			t.Errorf("expected countries ordered by ISO2 code, got %s before %s", countries[i-1].ISO2, country.ISO2)
+		}
+
+		switch country.ISO2 {
+		case "DE":
+			expected = model.Country{ISO2: "DE", Name: "GERMANY", BankCount: 1, HeadquarterCount: 1}
+		case "PL":
+			expected = model.Country{ISO2: "PL", Name: "POLAND", BankCount: 3, HeadquarterCount: 2}
+		default:
+			continue
+		}
+		if country != expected {
+			t.Errorf("expected %+v, got %+v", expected, country)
+		}
+	}
+
+	if countries[len(countries)-1].BankCount != 0 {
+		t.Errorf("expected no banks in %s, got %d", countries[len(countries)-1].ISO2, countries[len(countries)-1].BankCount)
+	}
+}
+
+func checkLoadResult(t *testing.T, result store.LoadResult, expected loadSummary) {
+	t.Helper()
+
+	var updated []string
+	for _, change := range result.Updated {
+		updated = append(updated, change.After.SWIFTCode)
+	}
+
+	summary := loadSummary{
+		inserted:  strings.Join(result.Inserted, " "),
+		updated:   strings.Join(updated, " "),
+		removed:   strings.Join(result.Removed, " "),
+		linked:    strings.Join(result.Linked, " "),
+		unchanged: result.Unchanged,
+	}
+
+	if summary != expected {
+		t.Errorf("expected load result %+v, got %+v", expected, summary)
+	}
+}
+
+func sliceSource(banks []model.Bank) store.BankSource {
+	return func(fn func(model.Bank) error) error {
+		for _, bank := range banks {
+			if err := fn(bank); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func newBank(swiftCode string) model.Bank {
+	townName := "WARSZAWA"
+	countryISO2 := swiftCode[4:6]
+
+	return model.Bank{
+		SWIFTCode:     swiftCode,
+		BankName:      "Bank " + swiftCode,
+		CountryISO2:   countryISO2,
+		CountryName:   "Country " + countryISO2,
+		IsHeadquarter: swiftCode[8:] == "XXX",
+		TownName:      &townName,
+	}
+}
+
+func create(t *testing.T, storage store.Storage, banks ...model.Bank) {
+	t.Helper()
+
+	for _, bank := range banks {
+		if err := storage.Banks.Create(context.Background(), &bank); err != nil {
+			t.Fatalf("cannot create bank %s: %v", bank.SWIFTCode, err)
+		}
+	}
+}
+
+func checkSWIFTCodes(t *testing.T, banks []model.Bank, expected ...string) {
+	t.Helper()
+
+	if len(banks) != len(expected) {
+		t.Fatalf("expected %d banks, got %d", len(expected), len(banks))
+	}
+
+	for i, bank := range banks {
+		if bank.SWIFTCode != expected[i] {
+			t.Errorf("expected bank %d to be %s, got %s", i, expected[i], bank.SWIFTCode)
+		}
+	}
+}
+
+// empty headquarterSwiftCode expects the bank to be unlinked
+func checkHeadquarterLink(t *testing.T, bank model.Bank, headquarterSwiftCode string) {
+	t.Helper()
+
+	switch {
+	case headquarterSwiftCode == "" && bank.HeadquarterSWIFTCode != nil:
+		t.Errorf("expected %s to be unlinked, got headquarter %s", bank.SWIFTCode, *bank.HeadquarterSWIFTCode)
+	case headquarterSwiftCode != "" && bank.HeadquarterSWIFTCode == nil:
+		t.Errorf("expected %s to be linked to %s, got unlinked", bank.SWIFTCode, headquarterSwiftCode)
+	case headquarterSwiftCode != "" && *bank.HeadquarterSWIFTCode != headquarterSwiftCode:
+		t.Errorf("expected %s to be linked to %s, got %s", bank.SWIFTCode, headquarterSwiftCode, *bank.HeadquarterSWIFTCode)
+	}
+}